@@ -4,9 +4,20 @@ import (
 	"net/http"
 	"notification-server/config"
 	"notification-server/middlewares"
+	auditControllers "notification-server/modules/audit-log/controllers"
+	auditRepositories "notification-server/modules/audit-log/repositories"
+	auditServices "notification-server/modules/audit-log/services"
 	connectionControllers "notification-server/modules/connection/controllers"
 	connectionRepositories "notification-server/modules/connection/repositories"
 	connectionServices "notification-server/modules/connection/services"
+	healthControllers "notification-server/modules/health/controllers"
+	healthServices "notification-server/modules/health/services"
+	notificationControllers "notification-server/modules/notification/controllers"
+	notificationRepositories "notification-server/modules/notification/repositories"
+	notificationServices "notification-server/modules/notification/services"
+	serviceAccountControllers "notification-server/modules/service-account/controllers"
+	serviceAccountRepositories "notification-server/modules/service-account/repositories"
+	serviceAccountServices "notification-server/modules/service-account/services"
 	userDeliveryControllers "notification-server/modules/user-delivery/controllers"
 	userDeliveryRepositories "notification-server/modules/user-delivery/repositories"
 	userDeliveryServices "notification-server/modules/user-delivery/services"
@@ -15,48 +26,113 @@ import (
 	webviewServices "notification-server/modules/webview-server/services"
 
 	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
 )
 
-func InitializeRouter() *echo.Echo {
+func InitializeRouter(deliveryPool healthServices.WorkerPool) *echo.Echo {
 	e := echo.New()
+	e.HTTPErrorHandler = middlewares.ErrorHandler
+	e.Use(otelecho.Middleware(config.TracingConfig.ServiceName, otelecho.WithSkipper(isProbeRequest)))
+	e.Use(middlewares.RequestID)
+	e.Use(middlewares.RequestLogger)
+	e.Use(middlewares.Metrics)
 
 	config.InitMongoDB()
 
 	webviewRepo := webviewRepositories.NewWebviewRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database), config.MongoDBClient)
 	userDeliveryRepo := userDeliveryRepositories.NewUserDeliveryRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database), config.MongoDBClient)
 	connectionRepo := connectionRepositories.NewConnectionRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database))
+	notificationRepo := notificationRepositories.NewNotificationRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database), config.MongoDBClient)
+	deadLetterRepo := notificationRepositories.NewDeadLetterRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database))
+	deliveryAttemptRepo := notificationRepositories.NewDeliveryAttemptRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database))
+	auditLogRepo := auditRepositories.NewAuditLogRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database))
+	serviceAccountRepo := serviceAccountRepositories.NewServiceAccountRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database))
+	refreshTokenRepo := serviceAccountRepositories.NewRefreshTokenRepository(config.MongoDBClient.Database(config.MongoDBConfig.Database))
 
-	webViewService := webviewServices.NewWebviewService(webviewRepo, connectionRepo)
-	userDeliveryService := userDeliveryServices.NewUserDeliveryService(userDeliveryRepo, connectionRepo, webviewRepo)
-	connectionService := connectionServices.NewConnectionService(connectionRepo, userDeliveryRepo, webviewRepo)
+	auditLogService := auditServices.NewAuditLogService(auditLogRepo)
+
+	webViewService := webviewServices.NewWebviewService(webviewRepo, connectionRepo, auditLogService)
+	userDeliveryService := userDeliveryServices.NewUserDeliveryService(userDeliveryRepo, connectionRepo, webviewRepo, auditLogService)
+	connectionService := connectionServices.NewConnectionService(connectionRepo, userDeliveryRepo, webviewRepo, auditLogService)
+	notificationService := notificationServices.NewNotificationService(notificationRepo, connectionRepo)
+	deadLetterService := notificationServices.NewDeadLetterService(deadLetterRepo, notificationRepo, connectionRepo)
+	deliveryAttemptService := notificationServices.NewDeliveryAttemptService(deliveryAttemptRepo, connectionRepo)
+	serviceAccountService := serviceAccountServices.NewServiceAccountService(serviceAccountRepo, refreshTokenRepo, auditLogService)
+	tokenService := serviceAccountServices.NewTokenService(serviceAccountRepo, refreshTokenRepo)
+	healthService := healthServices.NewHealthService(deliveryPool)
 
 	webViewController := webviewControllers.NewWebViewController(webViewService)
 	userDeliveryController := userDeliveryControllers.NewUserDeliveryController(userDeliveryService)
 	connectionController := connectionControllers.NewConnectionController(connectionService)
+	notificationController := notificationControllers.NewNotificationController(notificationService)
+	deadLetterController := notificationControllers.NewDeadLetterController(deadLetterService)
+	deliveryAttemptController := notificationControllers.NewDeliveryAttemptController(deliveryAttemptService)
+	auditLogController := auditControllers.NewAuditLogController(auditLogService)
+	serviceAccountController := serviceAccountControllers.NewServiceAccountController(serviceAccountService)
+	tokenController := serviceAccountControllers.NewTokenController(tokenService)
+	healthController := healthControllers.NewHealthController(healthService)
+
+	e.GET("/healthz", healthController.Liveness)
+	e.GET("/readyz", healthController.Readiness)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	e.POST("/notifications", notificationController.SendNotification, middlewares.ValidateApiKey(connectionRepo))
+	e.DELETE("/notifications/:id", notificationController.CancelNotification, middlewares.ValidateApiKey(connectionRepo))
 
-	e.Use(middlewares.ValidateToken)
+	e.POST("/auth/token", tokenController.IssueToken)
+	e.POST("/auth/revoke", tokenController.RevokeToken)
 
-	e.GET("/", func(c echo.Context) error {
+	admin := e.Group("", middlewares.ValidateToken)
+	canRead := middlewares.RequireRole(middlewares.RoleViewer)
+	canWrite := middlewares.RequireRole(middlewares.RoleOperator)
+	canAdmin := middlewares.RequireRole(middlewares.RoleAdmin)
+
+	admin.GET("/", func(c echo.Context) error {
 		return c.String(http.StatusOK, "Hello, This is Notification Server!")
 	})
 
-	e.GET("/webview-servers", webViewController.GetWebViewList)
-	e.POST("/webview-server", webViewController.CreateWebView)
-	e.PUT("/webview-server/:id", webViewController.UpdateWebView)
-	e.PATCH("/webview-server/:id/status", webViewController.ChangeWebViewStatus)
-	e.DELETE("/webview-server/:id", webViewController.DeleteWebview)
+	admin.GET("/webview-servers", webViewController.GetWebViewList, canRead)
+	admin.POST("/webview-server", webViewController.CreateWebView, canWrite)
+	admin.PUT("/webview-server/:id", webViewController.UpdateWebView, canWrite)
+	admin.PATCH("/webview-server/:id/status", webViewController.ChangeWebViewStatus, canWrite)
+	admin.DELETE("/webview-server/:id", webViewController.DeleteWebview, canAdmin)
+
+	admin.GET("/user-deliveries", userDeliveryController.GetUserDeliveryList, canRead)
+	admin.POST("/user-delivery", userDeliveryController.CreateUserDelivery, canWrite)
+	admin.PUT("/user-delivery/:id", userDeliveryController.UpdateUserDelivery, canWrite)
+	admin.PATCH("/user-delivery/:id/status", userDeliveryController.ChangeUserDeliveryStatus, canWrite)
+	admin.DELETE("/user-delivery/:id", userDeliveryController.DeleteUserDelivery, canAdmin)
 
-	e.GET("/user-deliveries", userDeliveryController.GetUserDeliveryList)
-	e.POST("/user-delivery", userDeliveryController.CreateUserDelivery)
-	e.PUT("/user-delivery/:id", userDeliveryController.UpdateUserDelivery)
-	e.PATCH("/user-delivery/:id/status", userDeliveryController.ChangeUserDeliveryStatus)
-	e.DELETE("/user-delivery/:id", userDeliveryController.DeleteUserDelivery)
+	admin.POST("/connection/new", connectionController.CreateConnection, canWrite)
+	admin.GET("/connections", connectionController.GetConnections, canRead)
+	admin.PATCH("/connection/:id/webhook", connectionController.UpdateWebHookUrl, canWrite)
+	admin.PATCH("/connection/:id/status", connectionController.ChangeConnectionStatus, canWrite)
+	admin.PATCH("/connection/:id/delivery-settings", connectionController.UpdateDeliverySettings, canWrite)
+	admin.POST("/connection/:id/rotate-keys", connectionController.RotateKeys, canAdmin)
+	admin.DELETE("/connection/:id", connectionController.DeleteConnection, canAdmin)
 
-	e.POST("/connection/new", connectionController.CreateConnection)
-	e.GET("/connections", connectionController.GetConnections)
-	e.PATCH("/connection/:id/webhook", connectionController.UpdateWebHookUrl)
-	e.PATCH("/connection/:id/status", connectionController.ChangeConnectionStatus)
-	e.DELETE("/connection/:id", connectionController.DeleteConnection)
+	admin.GET("/connection/:id/deliveries", deliveryAttemptController.GetDeliveryAttempts, canRead)
+	admin.GET("/connection/:id/dead-letters", deadLetterController.GetDeadLetters, canRead)
+	admin.POST("/connection/:id/dead-letters/replay", deadLetterController.ReplayDeadLetters, canWrite)
+	admin.POST("/connection/:id/dead-letters/:deadLetterId/replay", deadLetterController.ReplayDeadLetter, canWrite)
+
+	admin.GET("/audit-log", auditLogController.GetAuditLogs, canRead)
+
+	admin.GET("/service-accounts", serviceAccountController.GetServiceAccounts, canRead)
+	admin.POST("/service-account", serviceAccountController.CreateServiceAccount, canAdmin)
+	admin.PATCH("/service-account/:id/status", serviceAccountController.ChangeServiceAccountStatus, canAdmin)
+	admin.POST("/service-account/:id/revoke-tokens", serviceAccountController.RevokeTokens, canAdmin)
+	admin.DELETE("/service-account/:id", serviceAccountController.DeleteServiceAccount, canAdmin)
 
 	return e
 }
+
+// Probes and scrapes arrive every few seconds and would drown out real traffic in traces
+func isProbeRequest(c echo.Context) bool {
+	switch c.Request().URL.Path {
+	case "/healthz", "/readyz", "/metrics":
+		return true
+	}
+	return false
+}
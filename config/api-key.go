@@ -0,0 +1,25 @@
+package config
+
+import (
+	"encoding/hex"
+	"time"
+)
+
+type apiKeyConfig struct {
+	RotationGracePeriod time.Duration
+	EncryptionKey       []byte
+}
+
+var ApiKeyConfig apiKeyConfig
+
+func InitApiKeys() {
+	encryptionKey, err := hex.DecodeString(GetEnv("API_KEY_ENCRYPTION_KEY"))
+	if err != nil || len(encryptionKey) != 32 {
+		Fatal("API_KEY_ENCRYPTION_KEY must be 32 bytes encoded as 64 hex characters")
+	}
+
+	ApiKeyConfig = apiKeyConfig{
+		RotationGracePeriod: GetEnvDuration("API_KEY_ROTATION_GRACE_PERIOD", 24*time.Hour),
+		EncryptionKey:       encryptionKey,
+	}
+}
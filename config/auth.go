@@ -0,0 +1,78 @@
+package config
+
+import (
+	"time"
+)
+
+type authConfig struct {
+	Secret            []byte
+	JWKSURL           string
+	JWKSFile          string
+	JWKSCacheTTL      time.Duration
+	Issuer            string
+	Audience          string
+	AllowedAlgorithms []string
+	SigningAlgorithm  string
+	AccessTokenTTL    time.Duration
+	RefreshTokenTTL   time.Duration
+	// Owner given to servers and connections created before tenants existed
+	DefaultOwnerID string
+}
+
+var AuthConfig authConfig
+
+func InitAuth() {
+	AuthConfig = authConfig{
+		Secret:            []byte(GetEnvWithDefault("JWT_SECRET", "")),
+		JWKSURL:           GetEnvWithDefault("JWT_JWKS_URL", ""),
+		JWKSFile:          GetEnvWithDefault("JWT_JWKS_FILE", ""),
+		JWKSCacheTTL:      GetEnvDuration("JWT_JWKS_CACHE_TTL", 10*time.Minute),
+		Issuer:            GetEnvWithDefault("JWT_ISSUER", ""),
+		Audience:          GetEnvWithDefault("JWT_AUDIENCE", ""),
+		AllowedAlgorithms: GetEnvList("JWT_ALLOWED_ALGORITHMS"),
+		AccessTokenTTL:    GetEnvDuration("JWT_ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:   GetEnvDuration("JWT_REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		DefaultOwnerID:    GetEnvWithDefault("DEFAULT_OWNER_ID", ""),
+	}
+
+	hasJWKS := AuthConfig.JWKSURL != "" || AuthConfig.JWKSFile != ""
+	if AuthConfig.JWKSURL != "" && AuthConfig.JWKSFile != "" {
+		Fatal("Set only one of JWT_JWKS_URL and JWT_JWKS_FILE")
+	}
+	if len(AuthConfig.Secret) == 0 && !hasJWKS {
+		Fatal("Either JWT_SECRET or a JWKS source (JWT_JWKS_URL, JWT_JWKS_FILE) must be configured")
+	}
+
+	// Mặc định chỉ chấp nhận những thuật toán có khóa đã được cấu hình
+	if len(AuthConfig.AllowedAlgorithms) == 0 {
+		if len(AuthConfig.Secret) > 0 {
+			AuthConfig.AllowedAlgorithms = append(AuthConfig.AllowedAlgorithms, "HS256")
+		}
+		if hasJWKS {
+			AuthConfig.AllowedAlgorithms = append(AuthConfig.AllowedAlgorithms, "RS256", "ES256")
+		}
+	}
+
+	for _, algorithm := range AuthConfig.AllowedAlgorithms {
+		switch algorithm {
+		case "HS256", "HS384", "HS512":
+			if len(AuthConfig.Secret) == 0 {
+				Fatal("JWT_ALLOWED_ALGORITHMS contains an HS algorithm but JWT_SECRET is not set", "algorithm", algorithm)
+			}
+			// Token do server tự phát hành được ký bằng thuật toán HS đầu tiên
+			if AuthConfig.SigningAlgorithm == "" {
+				AuthConfig.SigningAlgorithm = algorithm
+			}
+		case "RS256", "RS384", "RS512", "ES256", "ES384", "ES512":
+			if !hasJWKS {
+				Fatal("JWT_ALLOWED_ALGORITHMS contains an asymmetric algorithm but no JWKS source is configured", "algorithm", algorithm)
+			}
+		default:
+			Fatal("Unsupported algorithm in JWT_ALLOWED_ALGORITHMS", "algorithm", algorithm)
+		}
+	}
+
+	if AuthConfig.AccessTokenTTL <= 0 || AuthConfig.RefreshTokenTTL <= 0 {
+		Fatal("JWT_ACCESS_TOKEN_TTL and JWT_REFRESH_TOKEN_TTL must be positive")
+	}
+}
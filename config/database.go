@@ -2,13 +2,13 @@ package config
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.opentelemetry.io/contrib/instrumentation/go.mongodb.org/mongo-driver/mongo/otelmongo"
 )
 
 type mongoConfig struct {
@@ -30,31 +30,30 @@ func InitMongoDB() {
 			Database: GetEnv("MONGODB_DATABASE"),
 		}
 
-		fmt.Printf("🔌 Connecting to MongoDB with URI: %s\n", MongoDBConfig.URI)
-		clientOptions := options.Client().ApplyURI(MongoDBConfig.URI)
+		clientOptions := options.Client().ApplyURI(MongoDBConfig.URI).SetMonitor(otelmongo.NewMonitor())
 
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 
 		client, err := mongo.Connect(ctx, clientOptions)
 		if err != nil {
-			log.Fatalf("❌ Failed to connect to MongoDB: %v", err)
+			Fatal("Failed to connect to MongoDB", "error", err)
 		}
 
 		// Kiểm tra kết nối MongoDB
 		err = client.Ping(ctx, nil)
 		if err != nil {
-			log.Fatalf("❌ MongoDB is not responding: %v", err)
+			Fatal("MongoDB is not responding", "error", err)
 		}
 
-		fmt.Println("✅ MongoDB connection successful!")
+		slog.Info("Connected to MongoDB", "database", MongoDBConfig.Database)
 		MongoDBClient = client
 	})
 }
 
 func GetCollection(collectionName string) *mongo.Collection {
 	if MongoDBClient == nil {
-		log.Fatal("❌ MongoDB is not initialized. Call InitMongoDB() first.")
+		Fatal("MongoDB is not initialized, call InitMongoDB first")
 	}
 	return MongoDBClient.Database(MongoDBConfig.Database).Collection(collectionName)
 }
@@ -64,8 +63,9 @@ func DisconnectMongoDB() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 		if err := MongoDBClient.Disconnect(ctx); err != nil {
-			log.Fatalf("❌ Error disconnecting MongoDB: %v", err)
+			slog.Error("Failed to disconnect MongoDB", "error", err)
+			return
 		}
-		fmt.Println("🔌 MongoDB connection closed.")
+		slog.Info("MongoDB connection closed")
 	}
 }
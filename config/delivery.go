@@ -0,0 +1,72 @@
+package config
+
+import (
+	"log/slog"
+	"time"
+)
+
+type deliveryConfig struct {
+	Workers          int
+	PollInterval     time.Duration
+	LeaseDuration    time.Duration
+	WebhookTimeout   time.Duration
+	MaxAttempts      int
+	BackoffBase      time.Duration
+	BackoffMax       time.Duration
+	IdempotencyTTL   time.Duration
+	SchedulerTick    time.Duration
+	MaxScheduleAhead time.Duration
+
+	CircuitFailureThreshold int
+	CircuitOpenDuration     time.Duration
+	CircuitSuspendAfter     time.Duration
+}
+
+var DeliveryConfig deliveryConfig
+
+func InitDelivery() {
+	DeliveryConfig = deliveryConfig{
+		Workers:          GetEnvInt("DELIVERY_WORKERS", 4),
+		PollInterval:     GetEnvDuration("DELIVERY_POLL_INTERVAL", time.Second),
+		LeaseDuration:    GetEnvDuration("DELIVERY_LEASE_DURATION", 30*time.Second),
+		WebhookTimeout:   GetEnvDuration("DELIVERY_WEBHOOK_TIMEOUT", 10*time.Second),
+		MaxAttempts:      GetEnvInt("DELIVERY_MAX_ATTEMPTS", 5),
+		BackoffBase:      GetEnvDuration("DELIVERY_BACKOFF_BASE", 5*time.Second),
+		BackoffMax:       GetEnvDuration("DELIVERY_BACKOFF_MAX", time.Hour),
+		IdempotencyTTL:   GetEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		SchedulerTick:    GetEnvDuration("DELIVERY_SCHEDULER_INTERVAL", time.Second),
+		MaxScheduleAhead: GetEnvDuration("DELIVERY_MAX_SCHEDULE_AHEAD", 365*24*time.Hour),
+
+		CircuitFailureThreshold: GetEnvInt("CIRCUIT_FAILURE_THRESHOLD", 5),
+		CircuitOpenDuration:     GetEnvDuration("CIRCUIT_OPEN_DURATION", 30*time.Second),
+		CircuitSuspendAfter:     GetEnvDuration("CIRCUIT_SUSPEND_AFTER", 15*time.Minute),
+	}
+
+	if DeliveryConfig.Workers < 1 {
+		Fatal("DELIVERY_WORKERS must be at least 1")
+	}
+	if DeliveryConfig.CircuitFailureThreshold < 1 {
+		Fatal("CIRCUIT_FAILURE_THRESHOLD must be at least 1")
+	}
+	if DeliveryConfig.MaxAttempts < 1 {
+		Fatal("DELIVERY_MAX_ATTEMPTS must be at least 1")
+	}
+	if DeliveryConfig.BackoffBase < 0 {
+		Fatal("DELIVERY_BACKOFF_BASE must not be negative")
+	}
+	if DeliveryConfig.BackoffMax < DeliveryConfig.BackoffBase {
+		Fatal("DELIVERY_BACKOFF_MAX must be at least DELIVERY_BACKOFF_BASE")
+	}
+	if DeliveryConfig.PollInterval <= 0 {
+		Fatal("DELIVERY_POLL_INTERVAL must be positive")
+	}
+	if DeliveryConfig.SchedulerTick <= 0 {
+		Fatal("DELIVERY_SCHEDULER_INTERVAL must be positive")
+	}
+	// Một worker chậm không được để lease hết hạn trước khi webhook timeout, nếu không sẽ bị gửi trùng
+	if DeliveryConfig.LeaseDuration <= DeliveryConfig.WebhookTimeout {
+		Fatal("DELIVERY_LEASE_DURATION must be longer than DELIVERY_WEBHOOK_TIMEOUT")
+	}
+
+	slog.Info("Delivery configured", "workers", DeliveryConfig.Workers)
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	LogFormatJSON = "json"
+	LogFormatText = "text"
+)
+
+type loggerConfig struct {
+	Level  slog.Level
+	Format string
+}
+
+var LoggerConfig loggerConfig
+
+// InitLogger replaces the default logger, which also routes the standard log package
+// through slog, so anything still using log.Printf ends up in the same stream.
+func InitLogger() {
+	LoggerConfig = loggerConfig{
+		Format: strings.ToLower(GetEnvWithDefault("LOG_FORMAT", LogFormatJSON)),
+	}
+	if err := LoggerConfig.Level.UnmarshalText([]byte(GetEnvWithDefault("LOG_LEVEL", "info"))); err != nil {
+		Fatal("LOG_LEVEL must be one of debug, info, warn or error", "error", err)
+	}
+
+	options := &slog.HandlerOptions{Level: LoggerConfig.Level}
+	var handler slog.Handler
+	switch LoggerConfig.Format {
+	case LogFormatJSON:
+		handler = slog.NewJSONHandler(os.Stdout, options)
+	case LogFormatText:
+		handler = slog.NewTextHandler(os.Stdout, options)
+	default:
+		Fatal("LOG_FORMAT must be json or text", "format", LoggerConfig.Format)
+	}
+
+	slog.SetDefault(slog.New(handler))
+}
+
+// Fatal logs at error level and exits; slog has no fatal level of its own.
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
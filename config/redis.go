@@ -2,7 +2,7 @@ package config
 
 import (
 	"fmt"
-	"log"
+	"log/slog"
 	"strconv"
 
 	"github.com/go-redis/redis/v7"
@@ -18,7 +18,7 @@ func InitRedis() {
 
 	db, err := strconv.Atoi(dbStr)
 	if err != nil {
-		log.Fatalf("❌ Error converting REDIS_DB: %v", err)
+		Fatal("Invalid environment variable", "key", "REDIS_DB", "error", err)
 	}
 
 	RedisClient = redis.NewClient(&redis.Options{
@@ -26,11 +26,22 @@ func InitRedis() {
 		Password: password,
 		DB:       db,
 	})
+	RedisClient.AddHook(redisTracingHook{})
 
 	_, err = RedisClient.Ping().Result()
 	if err != nil {
-		log.Fatalf("❌ Cannot connect to Redis: %v", err)
+		Fatal("Failed to connect to Redis", "error", err)
 	}
 
-	fmt.Println("✅ Redis connection successful!")
+	slog.Info("Connected to Redis", "addr", RedisClient.Options().Addr)
+}
+
+func CloseRedis() {
+	if RedisClient != nil {
+		if err := RedisClient.Close(); err != nil {
+			slog.Error("Failed to close Redis connection", "error", err)
+			return
+		}
+		slog.Info("Redis connection closed")
+	}
 }
@@ -0,0 +1,21 @@
+package config
+
+import (
+	"time"
+)
+
+type serverConfig struct {
+	ShutdownTimeout time.Duration
+}
+
+var ServerConfig serverConfig
+
+func InitServer() {
+	ServerConfig = serverConfig{
+		ShutdownTimeout: GetEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+	}
+
+	if ServerConfig.ShutdownTimeout <= 0 {
+		Fatal("SHUTDOWN_TIMEOUT must be positive")
+	}
+}
@@ -0,0 +1,131 @@
+package config
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/go-redis/redis/v7"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TracesExporterNone   = "none"
+	TracesExporterOTLP   = "otlp"
+	TracesExporterStdout = "stdout"
+)
+
+type tracingConfig struct {
+	Exporter    string
+	ServiceName string
+}
+
+var (
+	TracingConfig  tracingConfig
+	TracerProvider *sdktrace.TracerProvider
+)
+
+// InitTracing installs the global tracer provider and W3C propagator. The OTLP exporter
+// reads its endpoint and headers from the standard OTEL_EXPORTER_OTLP_* variables, and
+// sampling follows OTEL_TRACES_SAMPLER.
+func InitTracing() {
+	TracingConfig = tracingConfig{
+		Exporter:    strings.ToLower(GetEnvWithDefault("OTEL_TRACES_EXPORTER", TracesExporterNone)),
+		ServiceName: GetEnvWithDefault("OTEL_SERVICE_NAME", "notification-server"),
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch TracingConfig.Exporter {
+	case TracesExporterNone:
+		return
+	case TracesExporterOTLP:
+		exporter, err = otlptracehttp.New(context.Background())
+	case TracesExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
+	default:
+		Fatal("OTEL_TRACES_EXPORTER must be one of none, otlp or stdout", "exporter", TracingConfig.Exporter)
+	}
+	if err != nil {
+		Fatal("Failed to create trace exporter", "exporter", TracingConfig.Exporter, "error", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(TracingConfig.ServiceName)))
+	if err != nil {
+		Fatal("Failed to build trace resource", "error", err)
+	}
+
+	TracerProvider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(TracerProvider)
+
+	slog.Info("Tracing enabled", "exporter", TracingConfig.Exporter)
+}
+
+// ShutdownTracing flushes spans still buffered in the batcher.
+func ShutdownTracing(ctx context.Context) error {
+	if TracerProvider == nil {
+		return nil
+	}
+	return TracerProvider.Shutdown(ctx)
+}
+
+// redisTracingHook gives every Redis command a client span under the span in the
+// command's context, which callers provide through RedisClient.WithContext.
+type redisTracingHook struct{}
+
+func (redisTracingHook) BeforeProcess(ctx context.Context, cmd redis.Cmder) (context.Context, error) {
+	ctx, _ = otel.Tracer("notification-server/redis").Start(ctx, "redis "+cmd.Name(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, attribute.String("db.operation.name", cmd.Name())),
+	)
+	return ctx, nil
+}
+
+func (redisTracingHook) AfterProcess(ctx context.Context, cmd redis.Cmder) error {
+	endRedisSpan(ctx, cmd.Err())
+	return nil
+}
+
+func (redisTracingHook) BeforeProcessPipeline(ctx context.Context, cmds []redis.Cmder) (context.Context, error) {
+	ctx, _ = otel.Tracer("notification-server/redis").Start(ctx, "redis pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.DBSystemRedis, attribute.Int("db.redis.pipeline_length", len(cmds))),
+	)
+	return ctx, nil
+}
+
+func (redisTracingHook) AfterProcessPipeline(ctx context.Context, cmds []redis.Cmder) error {
+	var err error
+	for _, cmd := range cmds {
+		if cmd.Err() != nil && cmd.Err() != redis.Nil {
+			err = cmd.Err()
+			break
+		}
+	}
+	endRedisSpan(ctx, err)
+	return nil
+}
+
+func endRedisSpan(ctx context.Context, err error) {
+	span := trace.SpanFromContext(ctx)
+	// redis.Nil chỉ là cache miss, không phải lỗi
+	if err != nil && err != redis.Nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
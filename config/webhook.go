@@ -0,0 +1,35 @@
+package config
+
+import (
+	"log/slog"
+	"strings"
+)
+
+type webhookPolicyConfig struct {
+	HttpsOnly            bool
+	AllowPrivateNetworks bool
+	AllowedHosts         []string
+	DeniedHosts          []string
+}
+
+var WebhookPolicyConfig webhookPolicyConfig
+
+func InitWebhookPolicy() {
+	WebhookPolicyConfig = webhookPolicyConfig{
+		HttpsOnly:            GetEnvBool("WEBHOOK_HTTPS_ONLY", false),
+		AllowPrivateNetworks: GetEnvBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		AllowedHosts:         lowerAll(GetEnvList("WEBHOOK_ALLOWED_HOSTS")),
+		DeniedHosts:          lowerAll(GetEnvList("WEBHOOK_DENIED_HOSTS")),
+	}
+
+	if WebhookPolicyConfig.AllowPrivateNetworks {
+		slog.Warn("Webhooks may target private networks, do not use this setting in production")
+	}
+}
+
+func lowerAll(items []string) []string {
+	for i, item := range items {
+		items[i] = strings.ToLower(item)
+	}
+	return items
+}
@@ -0,0 +1,84 @@
+package helpers
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"notification-server/config"
+)
+
+const ApiKeyPrefixLength = 8
+
+var ErrInvalidCiphertext = errors.New("invalid api key ciphertext")
+
+func ApiKeyPrefix(key string) string {
+	if len(key) <= ApiKeyPrefixLength {
+		return key
+	}
+	return key[:ApiKeyPrefixLength]
+}
+
+func NewApiKeySalt() (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(salt), nil
+}
+
+func HashApiKey(key string, salt string) string {
+	sum := sha256.Sum256([]byte(salt + key))
+	return hex.EncodeToString(sum[:])
+}
+
+func CompareApiKeyHash(key string, salt string, hash string) bool {
+	return subtle.ConstantTimeCompare([]byte(HashApiKey(key, salt)), []byte(hash)) == 1
+}
+
+// EncryptApiKey seals keys the server has to read back, such as webhook signing
+// secrets, with AES-256-GCM under API_KEY_ENCRYPTION_KEY.
+func EncryptApiKey(key string) (string, error) {
+	gcm, err := apiKeyCipher()
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(key), nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func DecryptApiKey(ciphertext string) (string, error) {
+	gcm, err := apiKeyCipher()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", ErrInvalidCiphertext
+	}
+
+	key, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", ErrInvalidCiphertext
+	}
+	return string(key), nil
+}
+
+func apiKeyCipher() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(config.ApiKeyConfig.EncryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
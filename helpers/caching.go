@@ -1,19 +1,43 @@
 package helpers
 
 import (
+	"context"
 	"notification-server/config"
 	"time"
+
+	"github.com/go-redis/redis/v7"
 )
 
-func SetCache(key string, value string) error {
+func SetCache(ctx context.Context, key string, value string) error {
 	expiration := 1 * time.Minute
-	return config.RedisClient.Set(key, value, expiration).Err()
+	err := config.RedisClient.WithContext(ctx).Set(key, value, expiration).Err()
+	recordCacheWrite(key, err)
+	return err
+}
+
+func GetCache(ctx context.Context, key string) (string, error) {
+	value, err := config.RedisClient.WithContext(ctx).Get(key).Result()
+	switch {
+	case err == redis.Nil:
+		recordCacheLookup(key, "miss")
+	case err != nil:
+		recordCacheLookup(key, "error")
+	default:
+		recordCacheLookup(key, "hit")
+	}
+	return value, err
+}
+
+func DeleteCache(ctx context.Context, key string) error {
+	return config.RedisClient.WithContext(ctx).Del(key).Err()
 }
 
-func GetCache(key string) (string, error) {
-	return config.RedisClient.Get(key).Result()
+func SetCacheWithTTL(ctx context.Context, key string, value string, expiration time.Duration) error {
+	err := config.RedisClient.WithContext(ctx).Set(key, value, expiration).Err()
+	recordCacheWrite(key, err)
+	return err
 }
 
-func DeleteCache(key string) error {
-	return config.RedisClient.Del(key).Err()
+func SetCacheIfNotExists(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	return config.RedisClient.WithContext(ctx).SetNX(key, value, expiration).Result()
 }
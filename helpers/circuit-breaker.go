@@ -0,0 +1,117 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"notification-server/config"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+const (
+	CircuitClosed = "closed"
+	CircuitOpen   = "open"
+)
+
+// Breaker state is kept in Redis so that every replica sees the same circuit. Each
+// script runs atomically, which is what lets exactly one caller take the half-open
+// probe slot once the open period has elapsed.
+var circuitAllowScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local circuit = redis.call("HMGET", KEYS[1], "state", "openedAt", "probeAt")
+if circuit[1] ~= "open" then
+	return {1, 0, 0}
+end
+
+local openedAt = tonumber(circuit[2]) or now
+local probeAt = tonumber(circuit[3]) or now
+if now >= probeAt then
+	redis.call("HSET", KEYS[1], "probeAt", now + tonumber(ARGV[1]))
+	return {1, openedAt, 0}
+end
+
+return {0, openedAt, probeAt - now}
+`)
+
+var circuitFailureScript = redis.NewScript(`
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local failures = redis.call("HINCRBY", KEYS[1], "failures", 1)
+local state = redis.call("HGET", KEYS[1], "state")
+local openedAt = tonumber(redis.call("HGET", KEYS[1], "openedAt")) or 0
+
+if state ~= "open" and failures >= tonumber(ARGV[1]) then
+	openedAt = now
+	redis.call("HSET", KEYS[1], "state", "open", "openedAt", openedAt, "probeAt", now + tonumber(ARGV[2]))
+end
+
+redis.call("PEXPIRE", KEYS[1], tonumber(ARGV[3]))
+return {failures, openedAt}
+`)
+
+type CircuitState struct {
+	Allowed    bool
+	OpenedAt   time.Time
+	RetryAfter time.Duration
+}
+
+func CircuitBreakerKey(connectionId string) string {
+	return "circuit:connection:" + connectionId
+}
+
+// CircuitAllow reports whether a request may go through. While the circuit is open it
+// lets one probe through every openDuration and asks everyone else to wait.
+func CircuitAllow(ctx context.Context, key string, openDuration time.Duration) (CircuitState, error) {
+	result, err := circuitAllowScript.Run(config.RedisClient.WithContext(ctx), []string{key}, openDuration.Milliseconds()).Result()
+	if err != nil {
+		return CircuitState{}, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 3 {
+		return CircuitState{}, fmt.Errorf("unexpected circuit breaker script result: %v", result)
+	}
+
+	allowed, _ := values[0].(int64)
+	openedAt, _ := values[1].(int64)
+	retryAfter, _ := values[2].(int64)
+
+	state := CircuitState{
+		Allowed:    allowed == 1,
+		RetryAfter: time.Duration(retryAfter) * time.Millisecond,
+	}
+	if openedAt > 0 {
+		state.OpenedAt = time.UnixMilli(openedAt)
+	}
+
+	return state, nil
+}
+
+// CircuitRecordFailure counts one more consecutive failure and opens the circuit once
+// threshold is reached. It returns when the circuit was opened, or the zero time if it
+// is still closed.
+func CircuitRecordFailure(ctx context.Context, key string, threshold int, openDuration time.Duration, ttl time.Duration) (time.Time, error) {
+	result, err := circuitFailureScript.Run(config.RedisClient.WithContext(ctx), []string{key}, threshold, openDuration.Milliseconds(), ttl.Milliseconds()).Result()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return time.Time{}, fmt.Errorf("unexpected circuit breaker script result: %v", result)
+	}
+
+	openedAt, _ := values[1].(int64)
+	if openedAt == 0 {
+		return time.Time{}, nil
+	}
+	return time.UnixMilli(openedAt), nil
+}
+
+func CircuitRecordSuccess(ctx context.Context, key string) error {
+	return config.RedisClient.WithContext(ctx).Del(key).Err()
+}
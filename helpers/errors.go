@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Error kinds that services return and the HTTP error handler maps to a status code.
+// Check for them with errors.Is.
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrValidation         = errors.New("validation failed")
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+type kindError struct {
+	kind error
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() []error {
+	return []error{e.kind, e.err}
+}
+
+// The constructors format like fmt.Errorf, so a cause passed with %w stays reachable
+// through errors.Is and errors.As.
+func NotFoundError(format string, args ...any) error {
+	return &kindError{kind: ErrNotFound, err: fmt.Errorf(format, args...)}
+}
+
+func ConflictError(format string, args ...any) error {
+	return &kindError{kind: ErrConflict, err: fmt.Errorf(format, args...)}
+}
+
+func ValidationError(format string, args ...any) error {
+	return &kindError{kind: ErrValidation, err: fmt.Errorf(format, args...)}
+}
+
+func PreconditionFailedError(format string, args ...any) error {
+	return &kindError{kind: ErrPreconditionFailed, err: fmt.Errorf(format, args...)}
+}
@@ -0,0 +1,244 @@
+package helpers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"notification-server/config"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	maxJWKSSize      = 1 << 20
+	jwksFetchTimeout = 5 * time.Second
+	// An unknown kid usually means the issuer rotated its keys, but a stream of tokens
+	// with made-up kids must not turn into a stream of JWKS downloads
+	minJWKSRefreshInterval = 30 * time.Second
+)
+
+var (
+	ErrJWKSUnavailable = errors.New("jwks unavailable")
+	ErrJWKNotFound     = errors.New("no matching key in jwks")
+)
+
+type JWK struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type jwksKey struct {
+	alg string
+	key crypto.PublicKey
+}
+
+type jwksCache struct {
+	mu          sync.Mutex
+	keys        map[string]jwksKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+	refreshing  chan struct{}
+	refreshErr  error
+}
+
+var jwks = &jwksCache{}
+
+var jwksClient = &http.Client{Timeout: jwksFetchTimeout}
+
+// JWKSKey returns the verification key for kid from the configured JWKS. A token
+// without a kid is only accepted when the set holds a single key.
+func JWKSKey(ctx context.Context, kid string, alg string) (crypto.PublicKey, error) {
+	keys, err := jwksKeys(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := keys[kid]
+	if !ok && kid == "" && len(keys) == 1 {
+		for _, only := range keys {
+			key, ok = only, true
+		}
+	}
+	if !ok {
+		return nil, ErrJWKNotFound
+	}
+	if key.alg != "" && key.alg != alg {
+		return nil, fmt.Errorf("%w: key %s is for %s", ErrJWKNotFound, kid, key.alg)
+	}
+
+	return key.key, nil
+}
+
+// jwksKeys starts a refresh when the set is stale or lacks kid. The download runs outside
+// the lock on its own deadline and is shared by everyone waiting for it, so a slow issuer
+// does not serialize admin requests and a client hanging up does not abort the refresh.
+// Callers that already have their key keep using it while a refresh is in flight.
+func jwksKeys(ctx context.Context, kid string) (map[string]jwksKey, error) {
+	jwks.mu.Lock()
+	keys := jwks.keys
+	_, known := keys[kid]
+	stale := time.Since(jwks.fetchedAt) > config.AuthConfig.JWKSCacheTTL
+	done := jwks.refreshing
+	if done == nil && (stale || !known) && time.Since(jwks.attemptedAt) >= minJWKSRefreshInterval {
+		jwks.attemptedAt = time.Now()
+		done = make(chan struct{})
+		jwks.refreshing = done
+		go refreshJWKS(context.WithoutCancel(ctx), done)
+	}
+	jwks.mu.Unlock()
+
+	if known || done == nil {
+		return keys, nil
+	}
+
+	var err error
+	select {
+	case <-done:
+		jwks.mu.Lock()
+		keys, err = jwks.keys, jwks.refreshErr
+		jwks.mu.Unlock()
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if keys == nil {
+		return nil, fmt.Errorf("%w: %v", ErrJWKSUnavailable, err)
+	}
+	return keys, nil
+}
+
+func refreshJWKS(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ctx, cancel := context.WithTimeout(ctx, jwksFetchTimeout)
+	defer cancel()
+
+	keys, err := loadJWKS(ctx)
+
+	jwks.mu.Lock()
+	defer jwks.mu.Unlock()
+	jwks.refreshing = nil
+	jwks.refreshErr = err
+	if err == nil {
+		jwks.keys = keys
+		jwks.fetchedAt = time.Now()
+	}
+}
+
+func loadJWKS(ctx context.Context) (map[string]jwksKey, error) {
+	var body []byte
+	if config.AuthConfig.JWKSFile != "" {
+		data, err := os.ReadFile(config.AuthConfig.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	} else {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, config.AuthConfig.JWKSURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := jwksClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("jwks endpoint responded with status %d", resp.StatusCode)
+		}
+		body, err = io.ReadAll(io.LimitReader(resp.Body, maxJWKSSize))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var set struct {
+		Keys []JWK `json:"keys"`
+	}
+	if err := json.Unmarshal(body, &set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]jwksKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := parseJWK(jwk)
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = jwksKey{alg: jwk.Alg, key: key}
+	}
+
+	return keys, nil
+}
+
+func parseJWK(jwk JWK) (crypto.PublicKey, error) {
+	switch jwk.Kty {
+	case "RSA":
+		n, err := decodeJWKInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeJWKInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() < 3 || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", jwk.Crv)
+		}
+
+		x, err := decodeJWKInt(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeJWKInt(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", jwk.Kty)
+}
+
+func decodeJWKInt(value string) (*big.Int, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(bytes) == 0 {
+		return nil, errors.New("invalid base64url integer")
+	}
+	return new(big.Int).SetBytes(bytes), nil
+}
@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"notification-server/config"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var ErrTokenIssuanceDisabled = errors.New("token issuance requires JWT_SECRET and an HS algorithm in JWT_ALLOWED_ALGORITHMS")
+
+type JWTClaims struct {
+	UserID     string   `json:"user_id"`
+	SuperAdmin bool     `json:"super_admin"`
+	Roles      []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+// ParseJWT verifies the signature, algorithm, expiry, issuer and audience of an admin
+// token. Extra options are appended, e.g. to skip expiry checks when revoking.
+func ParseJWT(ctx context.Context, tokenString string, extra ...jwt.ParserOption) (*JWTClaims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(config.AuthConfig.AllowedAlgorithms),
+		jwt.WithExpirationRequired(),
+	}
+	if config.AuthConfig.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.AuthConfig.Issuer))
+	}
+	if config.AuthConfig.Audience != "" {
+		options = append(options, jwt.WithAudience(config.AuthConfig.Audience))
+	}
+	options = append(options, extra...)
+
+	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
+		return verificationKey(ctx, token)
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*JWTClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+	return claims, nil
+}
+
+// SignJWT mints a token with the shared secret. Keys from a JWKS are public only, so
+// the server can issue tokens only when an HS algorithm is configured.
+func SignJWT(claims JWTClaims) (string, error) {
+	algorithm := config.AuthConfig.SigningAlgorithm
+	if algorithm == "" {
+		return "", ErrTokenIssuanceDisabled
+	}
+
+	if config.AuthConfig.Issuer != "" {
+		claims.Issuer = config.AuthConfig.Issuer
+	}
+	if config.AuthConfig.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{config.AuthConfig.Audience}
+	}
+
+	return jwt.NewWithClaims(jwt.GetSigningMethod(algorithm), claims).SignedString(config.AuthConfig.Secret)
+}
+
+// verificationKey picks the key by algorithm family, so a token can never be verified
+// with a key meant for a different kind of signature.
+func verificationKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return config.AuthConfig.Secret, nil
+	case *jwt.SigningMethodRSA:
+		key, err := tokenJWKSKey(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if rsaKey, ok := key.(*rsa.PublicKey); ok {
+			return rsaKey, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		key, err := tokenJWKSKey(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		if ecdsaKey, ok := key.(*ecdsa.PublicKey); ok {
+			return ecdsaKey, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no key for algorithm %s", token.Method.Alg())
+}
+
+func tokenJWKSKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	return JWKSKey(ctx, kid, token.Method.Alg())
+}
@@ -0,0 +1,37 @@
+package helpers
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type loggerContextKey struct{}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// WithLogFields returns a context whose logger also carries args, e.g. the request ID
+// or the IDs of the entities being worked on.
+func WithLogFields(ctx context.Context, args ...any) context.Context {
+	logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger)
+	if !ok {
+		logger = slog.Default()
+	}
+	return WithLogger(ctx, logger.With(args...))
+}
+
+// Logger returns the logger bound to ctx, falling back to the default logger, with the
+// trace ID added so log lines can be matched to spans.
+func Logger(ctx context.Context) *slog.Logger {
+	logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger)
+	if !ok {
+		logger = slog.Default()
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		logger = logger.With("trace_id", spanContext.TraceID().String())
+	}
+	return logger
+}
@@ -0,0 +1,87 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "HTTP requests handled, by route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency, by route template and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	cacheLookupsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_lookups_total",
+		Help: "Redis cache lookups, by cache name and result (hit, miss, error).",
+	}, []string{"cache", "result"})
+
+	cacheWritesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_writes_total",
+		Help: "Redis cache writes, by cache name and result (ok, error).",
+	}, []string{"cache", "result"})
+
+	mongoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_operation_duration_seconds",
+		Help:    "Latency of repository methods talking to MongoDB.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository", "method"})
+
+	deliveryAttemptsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "notification_delivery_attempts_total",
+		Help: "Webhook delivery attempts, by connection and outcome (succeeded, retrying, failed).",
+	}, []string{"connection", "outcome"})
+
+	deliveryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "notification_delivery_duration_seconds",
+		Help:    "Latency of webhook deliveries to user delivery servers, by outcome.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
+
+func ObserveHTTPRequest(method string, route string, status int, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	httpRequestsTotal.WithLabelValues(method, route, statusLabel).Inc()
+	httpRequestDuration.WithLabelValues(method, route, statusLabel).Observe(duration.Seconds())
+}
+
+// ObserveMongoOperation is meant to be deferred with the start time evaluated on entry:
+//
+//	defer helpers.ObserveMongoOperation("connection", "GetConnectionByID", time.Now())
+func ObserveMongoOperation(repository string, method string, start time.Time) {
+	mongoOperationDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+}
+
+func RecordDeliveryAttempt(connectionID string, outcome string, latency time.Duration) {
+	deliveryAttemptsTotal.WithLabelValues(connectionID, outcome).Inc()
+	deliveryDuration.WithLabelValues(outcome).Observe(latency.Seconds())
+}
+
+func recordCacheLookup(key string, result string) {
+	cacheLookupsTotal.WithLabelValues(cacheName(key), result).Inc()
+}
+
+func recordCacheWrite(key string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	cacheWritesTotal.WithLabelValues(cacheName(key), result).Inc()
+}
+
+// Keys look like "webview_list:<tenant>:...", so the part before the first colon names
+// the cache without exploding label cardinality
+func cacheName(key string) string {
+	name, _, _ := strings.Cut(key, ":")
+	return name
+}
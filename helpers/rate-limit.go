@@ -0,0 +1,62 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"notification-server/config"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+// The bucket lives in a Redis hash and is refilled lazily on every call, using the Redis
+// clock so that replicas with drifting clocks still share one consistent limit.
+var tokenBucketScript = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+
+return {allowed, wait}
+`)
+
+// TakeRateLimitToken takes one token from the bucket identified by key. When the bucket
+// is empty it reports how long the caller should wait before a token is available.
+func TakeRateLimitToken(ctx context.Context, key string, ratePerSecond float64, burst int) (bool, time.Duration, error) {
+	result, err := tokenBucketScript.Run(config.RedisClient.WithContext(ctx), []string{key}, ratePerSecond, burst).Result()
+	if err != nil {
+		return false, 0, err
+	}
+
+	values, ok := result.([]interface{})
+	if !ok || len(values) != 2 {
+		return false, 0, fmt.Errorf("unexpected rate limit script result: %v", result)
+	}
+
+	allowed, _ := values[0].(int64)
+	waitMs, _ := values[1].(int64)
+
+	return allowed == 1, time.Duration(waitMs) * time.Millisecond, nil
+}
@@ -0,0 +1,90 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader           = "X-Notification-Signature"
+	DefaultSignatureTolerance = 5 * time.Minute
+)
+
+var (
+	ErrInvalidSignatureHeader = errors.New("invalid signature header")
+	ErrSignatureExpired       = errors.New("signature timestamp outside tolerance")
+	ErrSignatureMismatch      = errors.New("no signature matches the payload")
+)
+
+// SignPayload builds the signature header sent with every webhook:
+//
+//	t=<unix seconds>,v1=<hex hmac-sha256 of "<t>.<body>">
+//
+// One v1 entry is emitted per secret so a receiver can verify with either key.
+func SignPayload(secrets []string, body []byte, timestamp time.Time) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+
+	parts := []string{"t=" + ts}
+	for _, secret := range secrets {
+		parts = append(parts, "v1="+computeSignature(secret, ts, body))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// VerifySignature is the check a user-delivery server is expected to run on incoming
+// webhooks. It is kept here as the reference implementation of the scheme.
+func VerifySignature(header string, body []byte, secret string, tolerance time.Duration) error {
+	var ts string
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "t":
+			ts = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if ts == "" || len(signatures) == 0 {
+		return ErrInvalidSignatureHeader
+	}
+
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignatureHeader, err)
+	}
+
+	age := time.Since(time.Unix(unix, 0))
+	if age > tolerance || age < -tolerance {
+		return ErrSignatureExpired
+	}
+
+	expected := computeSignature(secret, ts, body)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+
+	return ErrSignatureMismatch
+}
+
+func computeSignature(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,106 @@
+package helpers
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifySignature(t *testing.T) {
+	body := []byte(`{"id":"1","payload":{"title":"hello"}}`)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		header  string
+		body    []byte
+		secret  string
+		wantErr error
+	}{
+		{
+			name:   "round trip",
+			header: SignPayload([]string{"current"}, body, now),
+			body:   body,
+			secret: "current",
+		},
+		{
+			name:   "timestamp within tolerance",
+			header: SignPayload([]string{"current"}, body, now.Add(-DefaultSignatureTolerance+time.Minute)),
+			body:   body,
+			secret: "current",
+		},
+		{
+			name:    "expired timestamp",
+			header:  SignPayload([]string{"current"}, body, now.Add(-DefaultSignatureTolerance-time.Minute)),
+			body:    body,
+			secret:  "current",
+			wantErr: ErrSignatureExpired,
+		},
+		{
+			name:    "timestamp too far in the future",
+			header:  SignPayload([]string{"current"}, body, now.Add(DefaultSignatureTolerance+time.Minute)),
+			body:    body,
+			secret:  "current",
+			wantErr: ErrSignatureExpired,
+		},
+		{
+			name:    "tampered body",
+			header:  SignPayload([]string{"current"}, body, now),
+			body:    []byte(`{"id":"1","payload":{"title":"hacked"}}`),
+			secret:  "current",
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name:    "wrong secret",
+			header:  SignPayload([]string{"current"}, body, now),
+			body:    body,
+			secret:  "other",
+			wantErr: ErrSignatureMismatch,
+		},
+		{
+			name:   "rotation, receiver still on the previous secret",
+			header: SignPayload([]string{"next", "previous"}, body, now),
+			body:   body,
+			secret: "previous",
+		},
+		{
+			name:   "rotation, receiver already on the new secret",
+			header: SignPayload([]string{"next", "previous"}, body, now),
+			body:   body,
+			secret: "next",
+		},
+		{
+			name:    "missing timestamp",
+			header:  "v1=abcdef",
+			body:    body,
+			secret:  "current",
+			wantErr: ErrInvalidSignatureHeader,
+		},
+		{
+			name:    "missing signature",
+			header:  "t=1700000000",
+			body:    body,
+			secret:  "current",
+			wantErr: ErrInvalidSignatureHeader,
+		},
+		{
+			name:    "malformed timestamp",
+			header:  "t=yesterday,v1=abcdef",
+			body:    body,
+			secret:  "current",
+			wantErr: ErrInvalidSignatureHeader,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.header, tt.body, tt.secret, DefaultSignatureTolerance)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("VerifySignature() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifySignature() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
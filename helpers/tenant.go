@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var ErrMissingTenant = errors.New("no tenant in request context")
+
+type Tenant struct {
+	OwnerID    string
+	SuperAdmin bool
+}
+
+type tenantContextKey struct{}
+
+func WithTenant(ctx context.Context, tenant Tenant) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// WithSystemTenant is for background work such as delivery workers and migrations,
+// which act on behalf of the server rather than of a caller.
+func WithSystemTenant(ctx context.Context) context.Context {
+	return WithTenant(ctx, Tenant{SuperAdmin: true})
+}
+
+func TenantFromContext(ctx context.Context) (Tenant, bool) {
+	tenant, ok := ctx.Value(tenantContextKey{}).(Tenant)
+	return tenant, ok
+}
+
+// TenantFilter restricts a query to the caller's documents. A context without a tenant
+// is rejected rather than left unscoped, so a missing middleware cannot leak data.
+func TenantFilter(ctx context.Context, filter bson.M) (bson.M, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok {
+		return nil, ErrMissingTenant
+	}
+	if tenant.SuperAdmin {
+		return filter, nil
+	}
+	if tenant.OwnerID == "" {
+		return nil, ErrMissingTenant
+	}
+
+	filter["ownerId"] = tenant.OwnerID
+	return filter, nil
+}
+
+func TenantOwnerID(ctx context.Context) (string, error) {
+	tenant, ok := TenantFromContext(ctx)
+	if !ok || tenant.OwnerID == "" {
+		return "", ErrMissingTenant
+	}
+	return tenant.OwnerID, nil
+}
+
+// TenantCacheKey namespaces cached responses, which would otherwise be shared between
+// tenants issuing the same query.
+func TenantCacheKey(ctx context.Context) string {
+	tenant, _ := TenantFromContext(ctx)
+	if tenant.SuperAdmin {
+		return "*"
+	}
+	return tenant.OwnerID
+}
@@ -0,0 +1,55 @@
+package helpers
+
+import (
+	"context"
+	"notification-server/config"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+)
+
+const (
+	revokedTokenPrefix   = "revoked_token:"
+	revokedSubjectPrefix = "revoked_subject:"
+)
+
+// RevokeToken denylists a single access token by its jti until the token would have
+// expired anyway.
+func RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return nil
+	}
+	return config.RedisClient.WithContext(ctx).Set(revokedTokenPrefix+jti, "1", ttl).Err()
+}
+
+// RevokeSubject rejects every token for subject issued up to now. The marker only has
+// to outlive the longest access token the server issues.
+func RevokeSubject(ctx context.Context, subject string) error {
+	return config.RedisClient.WithContext(ctx).Set(revokedSubjectPrefix+subject, strconv.FormatInt(time.Now().Unix(), 10), config.AuthConfig.AccessTokenTTL).Err()
+}
+
+func IsTokenRevoked(ctx context.Context, jti string, subject string, issuedAt time.Time) (bool, error) {
+	if jti == "" && subject == "" {
+		return false, nil
+	}
+
+	values, err := config.RedisClient.WithContext(ctx).MGet(revokedTokenPrefix+jti, revokedSubjectPrefix+subject).Result()
+	if err != nil && err != redis.Nil {
+		return false, err
+	}
+
+	if jti != "" && values[0] != nil {
+		return true, nil
+	}
+	if revokedAt, ok := values[1].(string); ok && subject != "" {
+		unix, err := strconv.ParseInt(revokedAt, 10, 64)
+		if err != nil {
+			return true, nil
+		}
+		return !issuedAt.After(time.Unix(unix, 0)), nil
+	}
+
+	return false, nil
+}
@@ -0,0 +1,151 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"notification-server/config"
+	"strings"
+	"syscall"
+)
+
+var ErrWebhookUrlNotAllowed = errors.New("webhook URL is not allowed")
+
+// Ranges not covered by netip.Addr's IsPrivate/IsLoopback/IsLinkLocal* helpers that
+// still must never be reachable from a webhook (carrier-grade NAT, benchmarking,
+// IETF-reserved, NAT64 and the like).
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// ValidateWebhookUrl checks a webhook target against the configured policy and resolves
+// its host, rejecting it if any address it points to is internal. It is meant for
+// create/update time; the dialer used by PostWebhook repeats the address check on every
+// connection so a DNS record changed later cannot sneak past it.
+func ValidateWebhookUrl(ctx context.Context, rawURL string) error {
+	parsed, err := checkWebhookUrlPolicy(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := parsed.Hostname()
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return checkWebhookAddr(addr)
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve host %s", ErrWebhookUrlNotAllowed, host)
+	}
+	for _, addr := range addrs {
+		if err := checkWebhookAddr(addr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkWebhookUrlPolicy(rawURL string) (*url.URL, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrWebhookUrlNotAllowed, err)
+	}
+
+	switch parsed.Scheme {
+	case "https":
+	case "http":
+		if config.WebhookPolicyConfig.HttpsOnly {
+			return nil, fmt.Errorf("%w: only https URLs are accepted", ErrWebhookUrlNotAllowed)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported scheme %q", ErrWebhookUrlNotAllowed, parsed.Scheme)
+	}
+
+	if parsed.User != nil {
+		return nil, fmt.Errorf("%w: credentials in the URL are not accepted", ErrWebhookUrlNotAllowed)
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if host == "" {
+		return nil, fmt.Errorf("%w: host is required", ErrWebhookUrlNotAllowed)
+	}
+
+	for _, pattern := range config.WebhookPolicyConfig.DeniedHosts {
+		if matchesHost(host, pattern) {
+			return nil, fmt.Errorf("%w: host %s is denied", ErrWebhookUrlNotAllowed, host)
+		}
+	}
+
+	if len(config.WebhookPolicyConfig.AllowedHosts) > 0 {
+		allowed := false
+		for _, pattern := range config.WebhookPolicyConfig.AllowedHosts {
+			if matchesHost(host, pattern) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("%w: host %s is not in the allowlist", ErrWebhookUrlNotAllowed, host)
+		}
+	}
+
+	return parsed, nil
+}
+
+// matchesHost supports exact hosts ("hooks.example.com") and wildcard subdomains
+// ("*.example.com", which does not match "example.com" itself).
+func matchesHost(host string, pattern string) bool {
+	if suffix, found := strings.CutPrefix(pattern, "*."); found {
+		return strings.HasSuffix(host, "."+suffix)
+	}
+	return host == pattern
+}
+
+func checkWebhookAddr(addr netip.Addr) error {
+	if config.WebhookPolicyConfig.AllowPrivateNetworks {
+		return nil
+	}
+
+	addr = addr.Unmap()
+	if addr.IsLoopback() || addr.IsPrivate() || addr.IsUnspecified() ||
+		addr.IsLinkLocalUnicast() || addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return fmt.Errorf("%w: address %s is internal", ErrWebhookUrlNotAllowed, addr)
+	}
+
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return fmt.Errorf("%w: address %s is reserved", ErrWebhookUrlNotAllowed, addr)
+		}
+	}
+
+	return nil
+}
+
+func webhookDialControl(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookUrlNotAllowed, err)
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebhookUrlNotAllowed, err)
+	}
+
+	return checkWebhookAddr(addr)
+}
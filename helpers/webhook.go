@@ -0,0 +1,99 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"notification-server/config"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const maxWebhookResponseBody = 4096
+
+var ErrInvalidWebhookRequest = errors.New("invalid webhook request")
+
+type WebhookResult struct {
+	StatusCode int
+	Body       string
+	Latency    time.Duration
+}
+
+var webhookClient = &http.Client{
+	Transport: &http.Transport{
+		// Không dùng proxy từ biến môi trường: proxy sẽ bỏ qua bước kiểm tra địa chỉ khi dial
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   webhookDialControl,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	},
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+func PostWebhook(ctx context.Context, url string, body []byte, headers map[string]string) (WebhookResult, error) {
+	if config.DeliveryConfig.WebhookTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.DeliveryConfig.WebhookTimeout)
+		defer cancel()
+	}
+
+	if _, err := checkWebhookUrlPolicy(url); err != nil {
+		return WebhookResult{}, err
+	}
+
+	ctx, span := StartSpan(ctx, "webhook POST", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		RecordSpanError(span, err)
+		return WebhookResult{}, fmt.Errorf("%w: %v", ErrInvalidWebhookRequest, err)
+	}
+	span.SetAttributes(attribute.String("http.request.method", http.MethodPost), attribute.String("server.address", req.URL.Hostname()))
+
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	// Receivers that trace can continue the trace of the delivery
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	start := time.Now()
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		RecordSpanError(span, err)
+		return WebhookResult{Latency: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxWebhookResponseBody))
+
+	return WebhookResult{
+		StatusCode: resp.StatusCode,
+		Body:       string(respBody),
+		Latency:    time.Since(start),
+	}, nil
+}
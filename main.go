@@ -1,18 +1,167 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
 	"notification-server/api"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"notification-server/config"
+	auditRepositories "notification-server/modules/audit-log/repositories"
+	connectionRepositories "notification-server/modules/connection/repositories"
+	notificationRepositories "notification-server/modules/notification/repositories"
+	notificationServices "notification-server/modules/notification/services"
+	notificationWorkers "notification-server/modules/notification/workers"
+	serviceAccountRepositories "notification-server/modules/service-account/repositories"
+	userDeliveryRepositories "notification-server/modules/user-delivery/repositories"
+	webviewRepositories "notification-server/modules/webview-server/repositories"
+
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 func main() {
 
 	// Initialize MongoDB connection
 	config.LoadEnv()
+	config.InitLogger()
+	config.InitTracing()
 	config.InitMongoDB()
 	config.InitRedis()
+	config.InitDelivery()
+	config.InitWebhookPolicy()
+	config.InitApiKeys()
+	config.InitAuth()
+	config.InitServer()
+
+	db := config.MongoDBClient.Database(config.MongoDBConfig.Database)
+	notificationRepo := notificationRepositories.NewNotificationRepository(db, config.MongoDBClient)
+	deadLetterRepo := notificationRepositories.NewDeadLetterRepository(db)
+	deliveryAttemptRepo := notificationRepositories.NewDeliveryAttemptRepository(db)
+	connectionRepo := connectionRepositories.NewConnectionRepository(db)
+	webviewRepo := webviewRepositories.NewWebviewRepository(db, config.MongoDBClient)
+	userDeliveryRepo := userDeliveryRepositories.NewUserDeliveryRepository(db, config.MongoDBClient)
+	auditLogRepo := auditRepositories.NewAuditLogRepository(db)
+	serviceAccountRepo := serviceAccountRepositories.NewServiceAccountRepository(db)
+	refreshTokenRepo := serviceAccountRepositories.NewRefreshTokenRepository(db)
+
+	if err := notificationRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create notification indexes", "error", err)
+	}
+	if err := deadLetterRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create dead letter indexes", "error", err)
+	}
+	if err := deliveryAttemptRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create delivery attempt indexes", "error", err)
+	}
+	if err := connectionRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create connection indexes", "error", err)
+	}
+	if err := auditLogRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create audit log indexes", "error", err)
+	}
+	if err := serviceAccountRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create service account indexes", "error", err)
+	}
+	if err := refreshTokenRepo.EnsureIndexes(context.Background()); err != nil {
+		config.Fatal("Failed to create refresh token indexes", "error", err)
+	}
+	if migrated, err := connectionRepo.MigratePlaintextApiKeys(context.Background()); err != nil {
+		config.Fatal("Failed to migrate plaintext API keys", "error", err)
+	} else if migrated > 0 {
+		slog.Info("Migrated API keys to hashed storage", "connections", migrated)
+	}
+	if config.AuthConfig.DefaultOwnerID != "" {
+		backfillOwner(context.Background(), config.AuthConfig.DefaultOwnerID, webviewRepo, userDeliveryRepo, connectionRepo)
+	}
+
+	prometheus.MustRegister(notificationServices.NewQueueCollector(notificationRepo))
+
+	deliveryService := notificationServices.NewDeliveryService(notificationRepo, deadLetterRepo, deliveryAttemptRepo, connectionRepo)
+	deliveryPool := notificationWorkers.NewDeliveryWorkerPool(notificationRepo, deliveryService, config.DeliveryConfig.Workers, config.DeliveryConfig.PollInterval, config.DeliveryConfig.LeaseDuration)
+	deliveryPool.Start(context.Background())
+
+	scheduler := notificationWorkers.NewScheduler(notificationRepo, config.DeliveryConfig.SchedulerTick)
+	scheduler.Start(context.Background())
+
+	e := api.InitializeRouter(deliveryPool)
+	e.HideBanner = true
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		slog.Info("HTTP server listening", "addr", ":1323")
+		if err := e.Start(":1323"); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			config.Fatal("HTTP server stopped", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	// Tín hiệu thứ hai sẽ tắt ngay lập tức
+	stop()
+	slog.Info("Shutting down", "timeout", config.ServerConfig.ShutdownTimeout.String())
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ServerConfig.ShutdownTimeout)
+	defer cancel()
+
+	// Workers stop claiming right away instead of waiting for the HTTP drain
+	workersStopped := make(chan error, 1)
+	go func() {
+		if err := scheduler.Stop(shutdownCtx); err != nil {
+			slog.Error("Scheduler did not stop in time", "error", err)
+		}
+		workersStopped <- deliveryPool.Stop(shutdownCtx)
+	}()
+
+	if err := e.Shutdown(shutdownCtx); err != nil {
+		slog.Error("HTTP server did not drain in time", "error", err)
+	}
+
+	if err := <-workersStopped; err != nil {
+		// A worker still in Deliver needs Mongo to record the result of a webhook that may
+		// already have been accepted, so give it up to one more webhook timeout.
+		finalCtx, cancelFinal := context.WithTimeout(context.Background(), config.DeliveryConfig.WebhookTimeout)
+		err = deliveryPool.Stop(finalCtx)
+		cancelFinal()
+		if err != nil {
+			slog.Warn("Delivery workers did not finish in time, their notifications will be retried after the lease expires", "error", err)
+		}
+	}
+
+	// Spans are flushed on a fresh deadline so a slow drain does not lose the traces of it
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := config.ShutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+
+	config.CloseRedis()
+	config.DisconnectMongoDB()
+	slog.Info("Shutdown complete")
+}
+
+// backfillOwner hands documents created before tenants existed to DEFAULT_OWNER_ID.
+// Without it they stay visible to super admins only.
+func backfillOwner(ctx context.Context, ownerID string, webviewRepo *webviewRepositories.WebViewRepository, userDeliveryRepo *userDeliveryRepositories.UserDeliveryRepository, connectionRepo *connectionRepositories.ConnectionRepository) {
+	webviews, err := webviewRepo.BackfillOwnerID(ctx, ownerID)
+	if err != nil {
+		config.Fatal("Failed to backfill webview server owners", "error", err)
+	}
+	userDeliveries, err := userDeliveryRepo.BackfillOwnerID(ctx, ownerID)
+	if err != nil {
+		config.Fatal("Failed to backfill user delivery server owners", "error", err)
+	}
+	connections, err := connectionRepo.BackfillOwnerID(ctx, ownerID)
+	if err != nil {
+		config.Fatal("Failed to backfill connection owners", "error", err)
+	}
 
-	e := api.InitializeRouter()
-	e.Logger.Fatal(e.Start(":1323"))
+	if webviews+userDeliveries+connections > 0 {
+		slog.Info("Assigned unowned documents to the default owner", "owner_id", ownerID, "webview_servers", webviews, "user_deliveries", userDeliveries, "connections", connections)
+	}
 }
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"notification-server/helpers"
+	connectionRepositories "notification-server/modules/connection/repositories"
+
+	"github.com/labstack/echo/v4"
+)
+
+const ApiKeyHeader = "X-Api-Key"
+
+func ValidateApiKey(connectionRepo *connectionRepositories.ConnectionRepository) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			apiKey := strings.TrimSpace(c.Request().Header.Get(ApiKeyHeader))
+			if apiKey == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Missing API key"})
+			}
+
+			// The key identifies the tenant, so the lookup itself cannot be scoped yet
+			systemCtx := helpers.WithSystemTenant(c.Request().Context())
+			connection, keyField, err := connectionRepo.GetActiveConnectionByWebviewApiKey(systemCtx, apiKey)
+			if err != nil {
+				return err
+			}
+			if connection.ID == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid API key"})
+			}
+
+			if err := connectionRepo.MarkApiKeyUsed(systemCtx, connection.ID, keyField); err != nil {
+				helpers.Logger(systemCtx).Error("Failed to record API key usage", "connection_id", connection.ID, "error", err)
+			}
+
+			// Connections created before tenants existed have no owner and keep their
+			// previous unscoped behaviour
+			tenant := helpers.Tenant{OwnerID: connection.OwnerID, SuperAdmin: connection.OwnerID == ""}
+			ctx := helpers.WithTenant(c.Request().Context(), tenant)
+			ctx = helpers.WithLogFields(ctx, "connection_id", connection.ID)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			c.Set("connection", connection)
+			return next(c)
+		}
+	}
+}
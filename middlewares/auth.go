@@ -1,20 +1,17 @@
 package middlewares
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
-	"notification-server/config"
+	"notification-server/helpers"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 )
 
-type JWTClaims struct {
-	UserID string `json:"user_id"`
-	jwt.RegisteredClaims
-}
-
 func ValidateToken(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		tokenHeader := c.Request().Header.Get("Authorization")
@@ -22,24 +19,43 @@ func ValidateToken(next echo.HandlerFunc) echo.HandlerFunc {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Missing token"})
 		}
 
-		tokenString := tokenHeader[len("Bearer "):]
-
-		token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
-			return []byte(config.GetEnv("JWT_SECRET")), nil
-		})
-
-		if err != nil {
+		tokenString, ok := strings.CutPrefix(tokenHeader, "Bearer ")
+		if !ok {
 			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid token"})
 		}
 
-		if claims, ok := token.Claims.(*JWTClaims); ok && token.Valid {
-			if claims.ExpiresAt.Time.Before(time.Now()) {
+		claims, err := helpers.ParseJWT(c.Request().Context(), tokenString)
+		if err != nil {
+			if errors.Is(err, jwt.ErrTokenExpired) {
 				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Token expired"})
 			}
-			c.Set("userID", claims.UserID)
-			return next(c)
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid token"})
+		}
+
+		if claims.UserID == "" {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid token"})
+		}
+
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		revoked, err := helpers.IsTokenRevoked(c.Request().Context(), claims.ID, claims.Subject, issuedAt)
+		if err != nil {
+			// Không kiểm tra được danh sách thu hồi thì từ chối, tránh chấp nhận token đã bị thu hồi
+			helpers.Logger(c.Request().Context()).Error("Failed to check token revocation", "error", err)
+			return c.JSON(http.StatusServiceUnavailable, map[string]string{"error": "Unable to verify token"})
+		}
+		if revoked {
+			return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Token revoked"})
 		}
 
-		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid token"})
+		c.Set("userID", claims.UserID)
+		c.Set("roles", claims.Roles)
+		c.Set("superAdmin", claims.SuperAdmin)
+		ctx := helpers.WithTenant(c.Request().Context(), helpers.Tenant{OwnerID: claims.UserID, SuperAdmin: claims.SuperAdmin})
+		ctx = helpers.WithLogFields(ctx, "user_id", claims.UserID)
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
 	}
 }
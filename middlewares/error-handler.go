@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"notification-server/helpers"
+
+	"github.com/labstack/echo/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrorHandler writes every error a handler returns in the same {"error": "..."} shape
+// that controllers use for their own validation responses.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, message := errorResponse(err)
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSON(status, map[string]string{"error": message})
+	}
+	if err != nil {
+		helpers.Logger(c.Request().Context()).Error("Failed to write error response", "error", err)
+	}
+}
+
+// errorResponse hides the message of unexpected errors; the request logger still records
+// the original error.
+func errorResponse(err error) (int, string) {
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &httpErr):
+		if message, ok := httpErr.Message.(string); ok {
+			return httpErr.Code, message
+		}
+		return httpErr.Code, fmt.Sprint(httpErr.Message)
+	case errors.Is(err, helpers.ErrNotFound):
+		return http.StatusNotFound, err.Error()
+	case errors.Is(err, helpers.ErrConflict):
+		return http.StatusConflict, err.Error()
+	case errors.Is(err, helpers.ErrValidation), errors.Is(err, primitive.ErrInvalidHex):
+		return http.StatusBadRequest, err.Error()
+	case errors.Is(err, helpers.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed, err.Error()
+	}
+	return http.StatusInternalServerError, "internal server error"
+}
@@ -0,0 +1,38 @@
+package middlewares
+
+import (
+	"time"
+
+	"notification-server/helpers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Metrics labels requests with the route template rather than the raw path, so IDs in
+// the URL do not create a new series per resource.
+func Metrics(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+
+		status := responseStatus(c, err)
+
+		route := c.Path()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		helpers.ObserveHTTPRequest(c.Request().Method, route, status, time.Since(start))
+		return err
+	}
+}
+
+// responseStatus reads the status from the returned error when there is one, because
+// Echo's error handler only writes the response after the middleware chain returns.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil {
+		return c.Response().Status
+	}
+	status, _ := errorResponse(err)
+	return status
+}
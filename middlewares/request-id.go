@@ -0,0 +1,39 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"notification-server/helpers"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	RequestIDHeader    = echo.HeaderXRequestID
+	maxRequestIDLength = 128
+)
+
+// RequestID keeps the caller's X-Request-ID, or generates one, so that audit entries
+// and logs can be tied back to the request that caused them.
+func RequestID(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		requestID := strings.TrimSpace(c.Request().Header.Get(RequestIDHeader))
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = newRequestID()
+		}
+
+		c.Response().Header().Set(RequestIDHeader, requestID)
+		ctx := helpers.WithRequestID(c.Request().Context(), requestID)
+		ctx = helpers.WithLogFields(ctx, "request_id", requestID)
+		c.SetRequest(c.Request().WithContext(ctx))
+		return next(c)
+	}
+}
+
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	_, _ = rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
@@ -0,0 +1,40 @@
+package middlewares
+
+import (
+	"log/slog"
+	"time"
+
+	"notification-server/helpers"
+
+	"github.com/labstack/echo/v4"
+)
+
+// RequestLogger writes one line per request. It reads the logger after the handler has
+// run, so fields added further down the chain, such as the user ID, are included.
+func RequestLogger(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		start := time.Now()
+		err := next(c)
+
+		status := responseStatus(c, err)
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+
+		args := []any{
+			"method", c.Request().Method,
+			"route", c.Path(),
+			"path", c.Request().URL.Path,
+			"status", status,
+			"latency_ms", time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			args = append(args, "error", err.Error())
+		}
+
+		ctx := c.Request().Context()
+		helpers.Logger(ctx).Log(ctx, level, "request completed", args...)
+		return err
+	}
+}
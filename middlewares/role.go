@@ -0,0 +1,46 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	RoleViewer   = "viewer"
+	RoleOperator = "operator"
+	RoleAdmin    = "admin"
+)
+
+// Mỗi role bao gồm quyền của các role có hạng thấp hơn
+var roleRank = map[string]int{
+	RoleViewer:   1,
+	RoleOperator: 2,
+	RoleAdmin:    3,
+}
+
+// RequireRole must run after ValidateToken, which stores the caller's roles on the
+// context. Super admins pass every check.
+func RequireRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if superAdmin, _ := c.Get("superAdmin").(bool); superAdmin {
+				return next(c)
+			}
+
+			roles, _ := c.Get("roles").([]string)
+			for _, granted := range roles {
+				if roleRank[granted] >= roleRank[role] {
+					return next(c)
+				}
+			}
+
+			return c.JSON(http.StatusForbidden, map[string]string{"error": "Forbidden: requires " + role + " role"})
+		}
+	}
+}
+
+func IsValidRole(role string) bool {
+	_, ok := roleRank[role]
+	return ok
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+	dto "notification-server/modules/audit-log/dtos"
+	"notification-server/modules/audit-log/models"
+	"notification-server/modules/audit-log/services"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultAuditLogPageSize = 50
+	maxAuditLogPageSize     = 200
+)
+
+type AuditLogController struct {
+	service *services.AuditLogService
+}
+
+func NewAuditLogController(service *services.AuditLogService) *AuditLogController {
+	return &AuditLogController{service: service}
+}
+
+func (c *AuditLogController) GetAuditLogs(ctx echo.Context) error {
+	var query dto.GetAuditLogs
+
+	if err := ctx.Bind(&query); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if query.TargetType != "" && !models.IsValidTargetType(query.TargetType) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid target type"})
+	}
+
+	if from := ctx.QueryParam("from"); from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "from must be an RFC 3339 timestamp"})
+		}
+		query.From = parsed
+	}
+	if to := ctx.QueryParam("to"); to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "to must be an RFC 3339 timestamp"})
+		}
+		query.To = parsed
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "to must not be before from"})
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultAuditLogPageSize
+	}
+	if query.Limit > maxAuditLogPageSize {
+		query.Limit = maxAuditLogPageSize
+	}
+
+	response, err := c.service.GetAuditLogs(ctx.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,7 @@
+package domain
+
+type AuditLogResponse struct {
+	Message string `json:"message"`
+	Code    int    `json:"code"`
+	Data    any    `json:"data"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "notification-server/modules/audit-log/models"
+
+type GetAuditLogs struct {
+	List          []models.AuditLog `json:"list"`
+	NextPageToken string            `json:"nextPageToken"`
+}
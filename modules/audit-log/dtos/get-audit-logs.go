@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type GetAuditLogs struct {
+	ActorId    string    `query:"actorId"`
+	TargetType string    `query:"targetType"`
+	TargetId   string    `query:"targetId"`
+	From       time.Time `json:"-"`
+	To         time.Time `json:"-"`
+	Limit      int       `query:"limit"`
+	PageToken  string    `query:"pageToken"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+const (
+	TargetWebviewServer  = "webview-server"
+	TargetUserDelivery   = "user-delivery"
+	TargetConnection     = "connection"
+	TargetServiceAccount = "service-account"
+)
+
+const (
+	ActionCreate       = "create"
+	ActionUpdate       = "update"
+	ActionChangeStatus = "change-status"
+	ActionDelete       = "delete"
+	ActionRotateKeys   = "rotate-keys"
+	ActionRevokeTokens = "revoke-tokens"
+)
+
+func IsValidTargetType(targetType string) bool {
+	switch targetType {
+	case TargetWebviewServer, TargetUserDelivery, TargetConnection, TargetServiceAccount:
+		return true
+	}
+	return false
+}
+
+type AuditChange struct {
+	Before any `bson:"before" json:"before"`
+	After  any `bson:"after" json:"after"`
+}
+
+type AuditLog struct {
+	ID         string                 `bson:"_id,omitempty" json:"_id"`
+	CreatedAt  time.Time              `bson:"createdAt" json:"createdAt"`
+	ActorID    string                 `bson:"actorId" json:"actorId"`
+	OwnerID    string                 `bson:"ownerId" json:"ownerId"`
+	Action     string                 `bson:"action" json:"action"`
+	TargetType string                 `bson:"targetType" json:"targetType"`
+	TargetID   string                 `bson:"targetId" json:"targetId"`
+	Changes    map[string]AuditChange `bson:"changes" json:"changes"`
+	RequestID  string                 `bson:"requestId,omitempty" json:"requestId,omitempty"`
+}
@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"notification-server/helpers"
+	"notification-server/modules/audit-log/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditLogRepository struct {
+	collection *mongo.Collection
+}
+
+func NewAuditLogRepository(db *mongo.Database) *AuditLogRepository {
+	// Diff values are free-form, so nested documents should decode as maps that
+	// serialise back to JSON objects
+	bsonOptions := &options.BSONOptions{DefaultDocumentM: true}
+	return &AuditLogRepository{
+		collection: db.Collection("audit-logs", options.Collection().SetBSONOptions(bsonOptions)),
+	}
+}
+
+func (r *AuditLogRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "ownerId", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "actorId", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "targetType", Value: 1}, {Key: "targetId", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
+
+func (r *AuditLogRepository) CreateAuditLog(ctx context.Context, entry *models.AuditLog) error {
+	defer helpers.ObserveMongoOperation("audit-log", "CreateAuditLog", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(entry.ID)
+	if err != nil {
+		return err
+	}
+
+	entryDocument := bson.M{
+		"_id":        objectID,
+		"createdAt":  entry.CreatedAt,
+		"actorId":    entry.ActorID,
+		"ownerId":    entry.OwnerID,
+		"action":     entry.Action,
+		"targetType": entry.TargetType,
+		"targetId":   entry.TargetID,
+		"changes":    entry.Changes,
+	}
+	if entry.RequestID != "" {
+		entryDocument["requestId"] = entry.RequestID
+	}
+
+	_, err = r.collection.InsertOne(ctx, entryDocument)
+	return err
+}
+
+func (r *AuditLogRepository) GetAuditLogs(ctx context.Context, actorId string, targetType string, targetId string, from time.Time, to time.Time, limit int, nextPageToken string) ([]models.AuditLog, string, error) {
+	defer helpers.ObserveMongoOperation("audit-log", "GetAuditLogs", time.Now())
+	var entries []models.AuditLog
+	filter := bson.M{}
+
+	if actorId != "" {
+		filter["actorId"] = actorId
+	}
+	if targetType != "" {
+		filter["targetType"] = targetType
+	}
+	if targetId != "" {
+		filter["targetId"] = targetId
+	}
+
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lte"] = to
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	if nextPageToken != "" {
+		tokenID, err := helpers.StringToObjectID(nextPageToken)
+		if err != nil {
+			return nil, "", helpers.ValidationError("invalid nextPageToken: %s", nextPageToken)
+		}
+		filter["_id"] = bson.M{"$gt": tokenID}
+	}
+
+	filter, err := helpers.TenantFilter(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limitInt64 := int64(limit)
+	cursor, err := r.collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limitInt64,
+		Sort:  bson.M{"_id": 1},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var lastID string
+	for cursor.Next(ctx) {
+		var entry models.AuditLog
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, "", err
+		}
+
+		entries = append(entries, entry)
+		lastID = entry.ID
+	}
+
+	return entries, lastID, nil
+}
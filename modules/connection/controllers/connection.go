@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"notification-server/helpers"
 	dto "notification-server/modules/connection/dtos"
 	"notification-server/modules/connection/models"
 	"notification-server/modules/connection/services"
@@ -10,6 +11,12 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	maxDeliveryAttemptsLimit = 50
+	maxRateLimitPerSecond    = 10000
+	maxRateLimitBurst        = 10000
+)
+
 type ConnectionController struct {
 	service *services.ConnectionService
 }
@@ -31,15 +38,26 @@ func (c *ConnectionController) CreateConnection(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "All fields must be non-empty"})
 	}
 
+	if err := helpers.ValidateWebhookUrl(ctx.Request().Context(), query.UserDeliveryServerWebHookUrl); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if message := validateDeliverySettings(query.MaxDeliveryAttempts, query.RateLimitPerSecond, query.RateLimitBurst); message != "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+
 	var connectionDto dto.CreateConnection = dto.CreateConnection{
 		UserDeliveryServerId:         query.UserDeliveryServerId,
 		UserDeliveryServerWebHookUrl: query.UserDeliveryServerWebHookUrl,
 		WebviewServerId:              query.WebviewServerId,
+		MaxDeliveryAttempts:          query.MaxDeliveryAttempts,
+		RateLimitPerSecond:           query.RateLimitPerSecond,
+		RateLimitBurst:               query.RateLimitBurst,
 	}
 
 	response, err := c.service.CreateConnection(ctx.Request().Context(), connectionDto)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, response)
@@ -52,13 +70,13 @@ func (c *ConnectionController) GetConnections(ctx echo.Context) error {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
 	}
 
-	if query.Status != "active" && query.Status != "inactive" {
+	if !models.IsValidStatus(query.Status) {
 		query.Status = "inactive"
 	}
 
 	response, err := c.service.GetConnections(ctx.Request().Context(), query)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -79,13 +97,81 @@ func (c *ConnectionController) UpdateWebHookUrl(ctx echo.Context) error {
 	if dto.UserDeliveryServerWebHookUrl == "" {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "userDeliveryWebHookUrl cannot be empty"})
 	}
+	if err := helpers.ValidateWebhookUrl(ctx.Request().Context(), dto.UserDeliveryServerWebHookUrl); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 
 	err := c.service.UpdateWebHookUrl(ctx.Request().Context(), dto)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return err
 	}
 
-	return ctx.JSON(http.StatusOK, map[string]string{"message": "Webhook URL updated successfully"})
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Webhook URL updated successfully, the connection stays inactive until the new URL is verified"})
+}
+
+func (c *ConnectionController) UpdateDeliverySettings(ctx echo.Context) error {
+	id := strings.TrimSpace(ctx.Param("id"))
+
+	var req dto.UpdateDeliverySettings
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+	req.ID = id
+
+	if req.ID == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "ID cannot be empty"})
+	}
+	if req.MaxDeliveryAttempts == nil && req.RateLimitPerSecond == nil && req.RateLimitBurst == nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "at least one setting is required"})
+	}
+
+	var maxDeliveryAttempts, rateLimitBurst int
+	var rateLimitPerSecond float64
+	if req.MaxDeliveryAttempts != nil {
+		maxDeliveryAttempts = *req.MaxDeliveryAttempts
+	}
+	if req.RateLimitPerSecond != nil {
+		rateLimitPerSecond = *req.RateLimitPerSecond
+	}
+	if req.RateLimitBurst != nil {
+		rateLimitBurst = *req.RateLimitBurst
+	}
+	if message := validateDeliverySettings(maxDeliveryAttempts, rateLimitPerSecond, rateLimitBurst); message != "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": message})
+	}
+
+	err := c.service.UpdateDeliverySettings(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]string{"message": "Delivery settings updated successfully"})
+}
+
+func (c *ConnectionController) RotateKeys(ctx echo.Context) error {
+	var req dto.RotateKeys
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+	req.ID = strings.TrimSpace(ctx.Param("id"))
+	req.Key = strings.TrimSpace(req.Key)
+	if req.Key == "" {
+		req.Key = models.ApiKeyBoth
+	}
+
+	if req.ID == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "ID cannot be empty"})
+	}
+	if !models.IsValidApiKeyType(req.Key) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "key must be one of webview, userDelivery or both"})
+	}
+
+	response, err := c.service.RotateKeys(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
 }
 
 func (c *ConnectionController) ChangeConnectionStatus(ctx echo.Context) error {
@@ -109,7 +195,7 @@ func (c *ConnectionController) ChangeConnectionStatus(ctx echo.Context) error {
 
 	response, err := c.service.ChangeConnectionStatus(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -127,8 +213,23 @@ func (c *ConnectionController) DeleteConnection(ctx echo.Context) error {
 
 	err := c.service.DeleteConnection(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, map[string]string{"message": "Connection deleted successfully"})
 }
+
+// validateDeliverySettings treats zero as "not set": the server-wide default for the
+// attempt limit and no rate limit at all.
+func validateDeliverySettings(maxDeliveryAttempts int, rateLimitPerSecond float64, rateLimitBurst int) string {
+	if maxDeliveryAttempts < 0 || maxDeliveryAttempts > maxDeliveryAttemptsLimit {
+		return "maxDeliveryAttempts must be between 0 (use the server default) and 50"
+	}
+	if rateLimitPerSecond < 0 || rateLimitPerSecond > maxRateLimitPerSecond {
+		return "rateLimitPerSecond must be between 0 and 10000"
+	}
+	if rateLimitBurst < 0 || rateLimitBurst > maxRateLimitBurst {
+		return "rateLimitBurst must be between 0 and 10000"
+	}
+	return ""
+}
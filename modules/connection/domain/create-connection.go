@@ -1,5 +1,7 @@
 package domain
 
 type CreateConnection struct {
-	ID string `json:"id"`
+	ID                       string `json:"id"`
+	WebviewServerApiKey      string `json:"webviewServerApiKey"`
+	UserDeliveryServerApiKey string `json:"userDeliveryServerApiKey"`
 }
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+type RotateKeys struct {
+	ID                       string    `json:"id"`
+	WebviewServerApiKey      string    `json:"webviewServerApiKey,omitempty"`
+	UserDeliveryServerApiKey string    `json:"userDeliveryServerApiKey,omitempty"`
+	PreviousKeyExpiresAt     time.Time `json:"previousKeyExpiresAt"`
+}
@@ -1,7 +1,10 @@
 package dto
 
 type CreateConnection struct {
-	UserDeliveryServerId string `json:"userDeliveryServerId"`
-	WebviewServerId      string `json:"webviewServerId"`
-	UserDeliveryServerWebHookUrl string `json:"userDeliveryServerWebHookUrl"` 
+	UserDeliveryServerId         string `json:"userDeliveryServerId"`
+	WebviewServerId              string `json:"webviewServerId"`
+	UserDeliveryServerWebHookUrl string `json:"userDeliveryServerWebHookUrl"`
+	MaxDeliveryAttempts          int     `json:"maxDeliveryAttempts"`
+	RateLimitPerSecond           float64 `json:"rateLimitPerSecond"`
+	RateLimitBurst               int     `json:"rateLimitBurst"`
 }
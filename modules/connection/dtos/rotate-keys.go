@@ -0,0 +1,6 @@
+package dto
+
+type RotateKeys struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
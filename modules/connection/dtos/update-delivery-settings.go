@@ -0,0 +1,8 @@
+package dto
+
+type UpdateDeliverySettings struct {
+	ID                  string   `json:"id"`
+	MaxDeliveryAttempts *int     `json:"maxDeliveryAttempts"`
+	RateLimitPerSecond  *float64 `json:"rateLimitPerSecond"`
+	RateLimitBurst      *int     `json:"rateLimitBurst"`
+}
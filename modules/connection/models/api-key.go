@@ -0,0 +1,75 @@
+package models
+
+import (
+	"notification-server/helpers"
+	"time"
+)
+
+const (
+	ApiKeyWebview      = "webview"
+	ApiKeyUserDelivery = "userDelivery"
+	ApiKeyBoth         = "both"
+)
+
+const (
+	WebviewApiKeyField              = "webviewServerKey"
+	PreviousWebviewApiKeyField      = "previousWebviewServerKey"
+	UserDeliveryApiKeyField         = "userDeliveryServerKey"
+	PreviousUserDeliveryApiKeyField = "previousUserDeliveryServerKey"
+)
+
+// ApiKey never holds a key in plaintext. Keys the server only checks are stored as a
+// salted hash; the user delivery key signs outgoing webhooks, so it is encrypted instead.
+type ApiKey struct {
+	Prefix     string    `bson:"prefix" json:"prefix"`
+	Salt       string    `bson:"salt,omitempty" json:"-"`
+	Hash       string    `bson:"hash,omitempty" json:"-"`
+	Ciphertext string    `bson:"ciphertext,omitempty" json:"-"`
+	ExpiresAt  time.Time `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	LastUsedAt time.Time `bson:"lastUsedAt,omitempty" json:"lastUsedAt,omitempty"`
+}
+
+func IsValidApiKeyType(key string) bool {
+	switch key {
+	case ApiKeyWebview, ApiKeyUserDelivery, ApiKeyBoth:
+		return true
+	}
+	return false
+}
+
+func NewHashedApiKey(key string) (ApiKey, error) {
+	salt, err := helpers.NewApiKeySalt()
+	if err != nil {
+		return ApiKey{}, err
+	}
+
+	return ApiKey{
+		Prefix: helpers.ApiKeyPrefix(key),
+		Salt:   salt,
+		Hash:   helpers.HashApiKey(key, salt),
+	}, nil
+}
+
+func NewEncryptedApiKey(key string) (ApiKey, error) {
+	ciphertext, err := helpers.EncryptApiKey(key)
+	if err != nil {
+		return ApiKey{}, err
+	}
+
+	return ApiKey{
+		Prefix:     helpers.ApiKeyPrefix(key),
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+func (k ApiKey) Matches(key string) bool {
+	return k.Hash != "" && k.Prefix == helpers.ApiKeyPrefix(key) && helpers.CompareApiKeyHash(key, k.Salt, k.Hash)
+}
+
+func (k ApiKey) Reveal() (string, error) {
+	return helpers.DecryptApiKey(k.Ciphertext)
+}
+
+func (k ApiKey) IsExpired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
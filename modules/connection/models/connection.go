@@ -9,9 +9,51 @@ type Connection struct {
 	CreatedAt                    time.Time `bson:"createdAt" json:"createdAt"`
 	UpdatedAt                    time.Time `bson:"updatedAt" json:"updatedAt"`
 	Status                       string    `bson:"status" json:"status"`
-	WebviewServerApiKey          string    `bson:"webviewServerApiKey" json:"webviewServerApiKey"`
-	UserDeliveryServerApiKey     string    `bson:"userDeliveryServerApiKey" json:"userDeliveryServerApiKey"`
+	SuspendedReason              string    `bson:"suspendedReason,omitempty" json:"suspendedReason,omitempty"`
+	SuspendedAt                  time.Time `bson:"suspendedAt,omitempty" json:"suspendedAt,omitempty"`
+	WebviewServerApiKey          ApiKey    `bson:"webviewServerKey" json:"webviewServerApiKey"`
+	UserDeliveryServerApiKey     ApiKey    `bson:"userDeliveryServerKey" json:"userDeliveryServerApiKey"`
 	WebviewServerId              string    `bson:"webviewServerId" json:"webviewServerId"`
 	UserDeliveryServerId         string    `bson:"userDeliveryServerId" json:"userDeliveryServerId"`
 	UserDeliveryServerWebHookUrl string    `bson:"userDeliveryServerWebHookUrl" json:"userDeliveryServerWebHookUrl"`
+	WebHookVerified              bool      `bson:"webHookVerified" json:"webHookVerified"`
+	WebHookVerifiedAt            time.Time `bson:"webHookVerifiedAt,omitempty" json:"webHookVerifiedAt,omitempty"`
+	MaxDeliveryAttempts          int       `bson:"maxDeliveryAttempts,omitempty" json:"maxDeliveryAttempts,omitempty"`
+	RateLimitPerSecond           float64   `bson:"rateLimitPerSecond,omitempty" json:"rateLimitPerSecond,omitempty"`
+	RateLimitBurst               int       `bson:"rateLimitBurst,omitempty" json:"rateLimitBurst,omitempty"`
+	OwnerID                      string    `bson:"ownerId" json:"ownerId"`
+
+	PreviousWebviewServerApiKey      *ApiKey `bson:"previousWebviewServerKey,omitempty" json:"previousWebviewServerApiKey,omitempty"`
+	PreviousUserDeliveryServerApiKey *ApiKey `bson:"previousUserDeliveryServerKey,omitempty" json:"previousUserDeliveryServerApiKey,omitempty"`
+}
+
+// MatchWebviewApiKey reports which stored key, if any, the presented key matches. The
+// previous key only counts until its rotation grace period ends.
+func (c Connection) MatchWebviewApiKey(key string, now time.Time) (string, bool) {
+	if c.WebviewServerApiKey.Matches(key) {
+		return WebviewApiKeyField, true
+	}
+	if c.PreviousWebviewServerApiKey != nil && !c.PreviousWebviewServerApiKey.IsExpired(now) && c.PreviousWebviewServerApiKey.Matches(key) {
+		return PreviousWebviewApiKeyField, true
+	}
+	return "", false
+}
+
+// SigningSecrets returns the keys outgoing webhooks are signed with: the current user
+// delivery key and, during a rotation's grace period, the previous one as well.
+func (c Connection) SigningSecrets(now time.Time) ([]string, error) {
+	secret, err := c.UserDeliveryServerApiKey.Reveal()
+	if err != nil {
+		return nil, err
+	}
+
+	secrets := []string{secret}
+	if c.PreviousUserDeliveryServerApiKey != nil && !c.PreviousUserDeliveryServerApiKey.IsExpired(now) {
+		previous, err := c.PreviousUserDeliveryServerApiKey.Reveal()
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, previous)
+	}
+	return secrets, nil
 }
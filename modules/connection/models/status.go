@@ -1,13 +1,14 @@
 package models
 
 const (
-	StatusActive   = "active"
-	StatusInactive = "inactive"
+	StatusActive    = "active"
+	StatusInactive  = "inactive"
+	StatusSuspended = "suspended"
 )
 
 func IsValidStatus(status string) bool {
 	switch status {
-	case StatusActive, StatusInactive:
+	case StatusActive, StatusInactive, StatusSuspended:
 		return true
 	}
 	return false
@@ -2,7 +2,6 @@ package repositories
 
 import (
 	"context"
-	"fmt"
 	"notification-server/helpers"
 	"notification-server/modules/connection/models"
 	"time"
@@ -23,13 +22,112 @@ func NewConnectionRepository(db *mongo.Database) *ConnectionRepository {
 	}
 }
 
-func (repo *ConnectionRepository) IsHavingSameConnection(userDeliveryId string, webviewServerId string) (bool, error) {
-	filter := bson.M{
+const apiKeyLastUsedResolution = time.Minute
+
+func (repo *ConnectionRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := repo.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: models.WebviewApiKeyField + ".prefix", Value: 1}}},
+		{Keys: bson.D{{Key: models.PreviousWebviewApiKeyField + ".prefix", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	return err
+}
+
+// MigratePlaintextApiKeys rewrites connections created before keys were stored hashed,
+// removing the plaintext fields. It is safe to run on every start.
+func (repo *ConnectionRepository) MigratePlaintextApiKeys(ctx context.Context) (int, error) {
+	defer helpers.ObserveMongoOperation("connection", "MigratePlaintextApiKeys", time.Now())
+	filter := bson.M{"$or": bson.A{
+		bson.M{"webviewServerApiKey": bson.M{"$exists": true}},
+		bson.M{"userDeliveryServerApiKey": bson.M{"$exists": true}},
+		bson.M{"previousWebviewServerApiKey": bson.M{"$exists": true}},
+		bson.M{"previousUserDeliveryServerApiKey": bson.M{"$exists": true}},
+	}}
+
+	cursor, err := repo.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	migrated := 0
+	for cursor.Next(ctx) {
+		var document struct {
+			ID                                        primitive.ObjectID `bson:"_id"`
+			WebviewServerApiKey                       string             `bson:"webviewServerApiKey"`
+			UserDeliveryServerApiKey                  string             `bson:"userDeliveryServerApiKey"`
+			PreviousWebviewServerApiKey               string             `bson:"previousWebviewServerApiKey"`
+			PreviousWebviewServerApiKeyExpiresAt      time.Time          `bson:"previousWebviewServerApiKeyExpiresAt"`
+			PreviousUserDeliveryServerApiKey          string             `bson:"previousUserDeliveryServerApiKey"`
+			PreviousUserDeliveryServerApiKeyExpiresAt time.Time          `bson:"previousUserDeliveryServerApiKeyExpiresAt"`
+		}
+		if err := cursor.Decode(&document); err != nil {
+			return migrated, err
+		}
+
+		set := bson.M{}
+		if document.WebviewServerApiKey != "" {
+			key, err := models.NewHashedApiKey(document.WebviewServerApiKey)
+			if err != nil {
+				return migrated, err
+			}
+			set[models.WebviewApiKeyField] = key
+		}
+		if document.UserDeliveryServerApiKey != "" {
+			key, err := models.NewEncryptedApiKey(document.UserDeliveryServerApiKey)
+			if err != nil {
+				return migrated, err
+			}
+			set[models.UserDeliveryApiKeyField] = key
+		}
+		if document.PreviousWebviewServerApiKey != "" {
+			key, err := models.NewHashedApiKey(document.PreviousWebviewServerApiKey)
+			if err != nil {
+				return migrated, err
+			}
+			key.ExpiresAt = document.PreviousWebviewServerApiKeyExpiresAt
+			set[models.PreviousWebviewApiKeyField] = key
+		}
+		if document.PreviousUserDeliveryServerApiKey != "" {
+			key, err := models.NewEncryptedApiKey(document.PreviousUserDeliveryServerApiKey)
+			if err != nil {
+				return migrated, err
+			}
+			key.ExpiresAt = document.PreviousUserDeliveryServerApiKeyExpiresAt
+			set[models.PreviousUserDeliveryApiKeyField] = key
+		}
+
+		update := bson.M{"$unset": bson.M{
+			"webviewServerApiKey":                       "",
+			"userDeliveryServerApiKey":                  "",
+			"previousWebviewServerApiKey":               "",
+			"previousWebviewServerApiKeyExpiresAt":      "",
+			"previousUserDeliveryServerApiKey":          "",
+			"previousUserDeliveryServerApiKeyExpiresAt": "",
+		}}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+
+		if _, err := repo.collection.UpdateOne(ctx, bson.M{"_id": document.ID}, update); err != nil {
+			return migrated, err
+		}
+		migrated++
+	}
+
+	return migrated, cursor.Err()
+}
+
+func (repo *ConnectionRepository) IsHavingSameConnection(ctx context.Context, userDeliveryId string, webviewServerId string) (bool, error) {
+	defer helpers.ObserveMongoOperation("connection", "IsHavingSameConnection", time.Now())
+	filter, err := helpers.TenantFilter(ctx, bson.M{
 		"userDeliveryServerId": userDeliveryId,
 		"webviewServerId":      webviewServerId,
+	})
+	if err != nil {
+		return false, err
 	}
 
-	count, err := repo.collection.CountDocuments(context.TODO(), filter)
+	count, err := repo.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return false, err
 	}
@@ -37,7 +135,8 @@ func (repo *ConnectionRepository) IsHavingSameConnection(userDeliveryId string,
 	return count > 0, nil
 }
 
-func (repo *ConnectionRepository) CreateConnection(connect models.Connection) error {
+func (repo *ConnectionRepository) CreateConnection(ctx context.Context, connect models.Connection) error {
+	defer helpers.ObserveMongoOperation("connection", "CreateConnection", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(connect.ID)
 	if err != nil {
 		return err
@@ -48,25 +147,31 @@ func (repo *ConnectionRepository) CreateConnection(connect models.Connection) er
 		"createdAt":                    connect.CreatedAt,
 		"updatedAt":                    connect.UpdatedAt,
 		"status":                       connect.Status,
-		"webviewServerApiKey":          connect.WebviewServerApiKey,
-		"userDeliveryServerApiKey":     connect.UserDeliveryServerApiKey,
+		models.WebviewApiKeyField:      connect.WebviewServerApiKey,
+		models.UserDeliveryApiKeyField: connect.UserDeliveryServerApiKey,
 		"webviewServerId":              connect.WebviewServerId,
 		"userDeliveryServerId":         connect.UserDeliveryServerId,
 		"userDeliveryServerWebHookUrl": connect.UserDeliveryServerWebHookUrl,
+		"webHookVerified":              connect.WebHookVerified,
+		"maxDeliveryAttempts":          connect.MaxDeliveryAttempts,
+		"rateLimitPerSecond":           connect.RateLimitPerSecond,
+		"rateLimitBurst":               connect.RateLimitBurst,
+		"ownerId":                      connect.OwnerID,
 	}
 
-	_, err = repo.collection.InsertOne(context.TODO(), newConnection)
+	_, err = repo.collection.InsertOne(ctx, newConnection)
 	return err
 }
 
 func (repo *ConnectionRepository) GetConnections(ctx context.Context, userDeliveryId string, webviewID string, status string, limit int, nextPageToken string) ([]models.Connection, string, error) {
+	defer helpers.ObserveMongoOperation("connection", "GetConnections", time.Now())
 	var connections []models.Connection
 	filter := bson.M{}
 
 	if userDeliveryId != "" {
 		objectID, err := helpers.StringToObjectID(userDeliveryId)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid userDeliveryId: %s", userDeliveryId)
+			return nil, "", helpers.ValidationError("invalid userDeliveryId: %s", userDeliveryId)
 		}
 		filter["userDeliveryServerId"] = objectID
 	}
@@ -74,7 +179,7 @@ func (repo *ConnectionRepository) GetConnections(ctx context.Context, userDelive
 	if webviewID != "" {
 		objectID, err := helpers.StringToObjectID(webviewID)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid webviewID: %s", webviewID)
+			return nil, "", helpers.ValidationError("invalid webviewID: %s", webviewID)
 		}
 		filter["webviewServerId"] = objectID
 	}
@@ -86,10 +191,16 @@ func (repo *ConnectionRepository) GetConnections(ctx context.Context, userDelive
 	if nextPageToken != "" {
 		tokenID, err := helpers.StringToObjectID(nextPageToken)
 		if err != nil {
-			return nil, "", fmt.Errorf("invalid nextPageToken: %s", nextPageToken)
+			return nil, "", helpers.ValidationError("invalid nextPageToken: %s", nextPageToken)
 		}
 		filter["_id"] = bson.M{"$gt": tokenID}
 	}
+
+	filter, err := helpers.TenantFilter(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -118,16 +229,20 @@ func (repo *ConnectionRepository) GetConnections(ctx context.Context, userDelive
 }
 
 func (repo *ConnectionRepository) IsHavingConnectionById(ctx context.Context, connectionId string) (bool, error) {
+	defer helpers.ObserveMongoOperation("connection", "IsHavingConnectionById", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(connectionId)
 	if err != nil {
 		return false, err
 	}
 
-	filter := bson.M{
+	filter, err := helpers.TenantFilter(ctx, bson.M{
 		"_id": objectID,
+	})
+	if err != nil {
+		return false, err
 	}
 
-	count, err := repo.collection.CountDocuments(context.TODO(), filter)
+	count, err := repo.collection.CountDocuments(ctx, filter)
 	if err != nil {
 		return false, err
 	}
@@ -136,31 +251,125 @@ func (repo *ConnectionRepository) IsHavingConnectionById(ctx context.Context, co
 }
 
 func (repo *ConnectionRepository) UpdateUserDeliveryHookUrl(ctx context.Context, id string, newUserDeliveryHookUrl string) error {
+	defer helpers.ObserveMongoOperation("connection", "UpdateUserDeliveryHookUrl", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// Địa chỉ mới phải được xác minh lại trước khi connection được bật lại
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"userDeliveryServerWebHookUrl": newUserDeliveryHookUrl,
+			"webHookVerified":              false,
+			"status":                       models.StatusInactive,
+			"updatedAt":                    time.Now(),
+		},
+		"$unset": bson.M{"webHookVerifiedAt": ""},
+	}
+
+	_, err = repo.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (repo *ConnectionRepository) MarkWebHookVerified(ctx context.Context, id string, verifiedUrl string) (bool, error) {
+	defer helpers.ObserveMongoOperation("connection", "MarkWebHookVerified", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID, "userDeliveryServerWebHookUrl": verifiedUrl})
+	if err != nil {
+		return false, err
+	}
+	update := bson.M{"$set": bson.M{
+		"webHookVerified":   true,
+		"webHookVerifiedAt": now,
+		"updatedAt":         now,
+	}}
+
+	result, err := repo.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+func (repo *ConnectionRepository) UpdateDeliverySettings(ctx context.Context, id string, settings bson.M) error {
+	defer helpers.ObserveMongoOperation("connection", "UpdateDeliverySettings", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{"_id": objectID}
-	update := bson.M{"$set": bson.M{"userDeliveryServerWebHookUrl": newUserDeliveryHookUrl}}
+	set := bson.M{"updatedAt": time.Now()}
+	for key, value := range settings {
+		set[key] = value
+	}
 
-	_, err = repo.collection.UpdateOne(context.TODO(), filter, update)
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	_, err = repo.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
 	return err
 }
 
+// RotateKeys applies the new keys only if the current ones are still those the rotation
+// was computed from, so two concurrent rotations cannot both demote the same key.
+func (repo *ConnectionRepository) RotateKeys(ctx context.Context, id string, current bson.M, set bson.M) (bool, error) {
+	defer helpers.ObserveMongoOperation("connection", "RotateKeys", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return false, err
+	}
+	for key, value := range current {
+		filter[key] = value
+	}
+	set["updatedAt"] = time.Now()
+
+	result, err := repo.collection.UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
 func (r *ConnectionRepository) ChangeConnectionStatus(ctx context.Context, id string, status string) (string, error) {
+	defer helpers.ObserveMongoOperation("connection", "ChangeConnectionStatus", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"status": status,
 		},
 	}
+	if status != models.StatusSuspended {
+		update["$unset"] = bson.M{"suspendedReason": "", "suspendedAt": ""}
+	}
 
-	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	_, err = r.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return "", err
 	}
@@ -168,14 +377,50 @@ func (r *ConnectionRepository) ChangeConnectionStatus(ctx context.Context, id st
 	return id, nil
 }
 
+func (r *ConnectionRepository) SuspendConnection(ctx context.Context, id string, reason string) (bool, error) {
+	defer helpers.ObserveMongoOperation("connection", "SuspendConnection", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":          models.StatusSuspended,
+			"suspendedReason": reason,
+			"suspendedAt":     now,
+			"updatedAt":       now,
+		},
+	}
+
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID, "status": models.StatusActive})
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
 func (r *ConnectionRepository) GetConnectionByID(ctx context.Context, id string) (models.Connection, error) {
+	defer helpers.ObserveMongoOperation("connection", "GetConnectionByID", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return models.Connection{}, err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return models.Connection{}, err
+	}
+
 	var connection models.Connection
-	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&connection)
+	err = r.collection.FindOne(ctx, filter).Decode(&connection)
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
 			return models.Connection{}, nil
@@ -186,8 +431,74 @@ func (r *ConnectionRepository) GetConnectionByID(ctx context.Context, id string)
 	return connection, nil
 }
 
+// GetActiveConnectionByWebviewApiKey narrows the search down with the key prefix and
+// then checks the salted hash, returning the field of the key that matched.
+func (r *ConnectionRepository) GetActiveConnectionByWebviewApiKey(ctx context.Context, apiKey string) (models.Connection, string, error) {
+	defer helpers.ObserveMongoOperation("connection", "GetActiveConnectionByWebviewApiKey", time.Now())
+	prefix := helpers.ApiKeyPrefix(apiKey)
+	filter, err := helpers.TenantFilter(ctx, bson.M{
+		"$or": bson.A{
+			bson.M{models.WebviewApiKeyField + ".prefix": prefix},
+			bson.M{models.PreviousWebviewApiKeyField + ".prefix": prefix},
+		},
+		"status": models.StatusActive,
+	})
+	if err != nil {
+		return models.Connection{}, "", err
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return models.Connection{}, "", err
+	}
+	defer cursor.Close(ctx)
+
+	now := time.Now()
+	for cursor.Next(ctx) {
+		var connection models.Connection
+		if err := cursor.Decode(&connection); err != nil {
+			return models.Connection{}, "", err
+		}
+		if field, ok := connection.MatchWebviewApiKey(apiKey, now); ok {
+			return connection, field, nil
+		}
+	}
+
+	return models.Connection{}, "", cursor.Err()
+}
+
+// MarkApiKeyUsed records when a key was last used, at most once per
+// apiKeyLastUsedResolution so busy integrations do not write on every request.
+func (r *ConnectionRepository) MarkApiKeyUsed(ctx context.Context, id string, field string) error {
+	defer helpers.ObserveMongoOperation("connection", "MarkApiKeyUsed", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	filter, err := helpers.TenantFilter(ctx, bson.M{
+		"_id": objectID,
+		field: bson.M{"$exists": true},
+		"$or": bson.A{
+			bson.M{field + ".lastUsedAt": bson.M{"$exists": false}},
+			bson.M{field + ".lastUsedAt": bson.M{"$lt": now.Add(-apiKeyLastUsedResolution)}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{field + ".lastUsedAt": now}})
+	return err
+}
+
 func (repo *ConnectionRepository) GetConnectionByUserDeliveryId(ctx context.Context, userDeliveryId string) ([]models.Connection, error) {
-	filter := bson.M{"userDeliveryServerId": userDeliveryId}
+	defer helpers.ObserveMongoOperation("connection", "GetConnectionByUserDeliveryId", time.Now())
+	filter, err := helpers.TenantFilter(ctx, bson.M{"userDeliveryServerId": userDeliveryId})
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -215,7 +526,11 @@ func (repo *ConnectionRepository) GetConnectionByUserDeliveryId(ctx context.Cont
 }
 
 func (repo *ConnectionRepository) GetConnectionByWebviewId(ctx context.Context, webviewId string) ([]models.Connection, error) {
-	filter := bson.M{"webviewServerId": webviewId}
+	defer helpers.ObserveMongoOperation("connection", "GetConnectionByWebviewId", time.Now())
+	filter, err := helpers.TenantFilter(ctx, bson.M{"webviewServerId": webviewId})
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -243,12 +558,27 @@ func (repo *ConnectionRepository) GetConnectionByWebviewId(ctx context.Context,
 }
 
 func (repo *ConnectionRepository) DeleteConnection(ctx context.Context, id string) error {
+	defer helpers.ObserveMongoOperation("connection", "DeleteConnection", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return err
 	}
 
-	filter := bson.M{"_id": objectID}
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
 	_, err = repo.collection.DeleteOne(ctx, filter)
 	return err
 }
+
+// BackfillOwnerID assigns connections created before tenants existed to ownerID. It is
+// safe to run on every start.
+func (repo *ConnectionRepository) BackfillOwnerID(ctx context.Context, ownerID string) (int64, error) {
+	defer helpers.ObserveMongoOperation("connection", "BackfillOwnerID", time.Now())
+	result, err := repo.collection.UpdateMany(ctx, bson.M{"ownerId": bson.M{"$in": bson.A{nil, ""}}}, bson.M{"$set": bson.M{"ownerId": ownerID}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
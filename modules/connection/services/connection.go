@@ -7,7 +7,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"notification-server/config"
 	"notification-server/helpers"
+	auditModels "notification-server/modules/audit-log/models"
+	auditServices "notification-server/modules/audit-log/services"
 	"notification-server/modules/connection/domain"
 	dto "notification-server/modules/connection/dtos"
 	"notification-server/modules/connection/models"
@@ -16,59 +19,74 @@ import (
 	webviewRepositories "notification-server/modules/webview-server/repositories"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 type ConnectionService struct {
 	connectionRepo   *connectionRepositories.ConnectionRepository
 	userDeliveryRepo *userDeliveryRepositories.UserDeliveryRepository
 	webviewRepo      *webviewRepositories.WebViewRepository
+	auditService     *auditServices.AuditLogService
 }
 
-func NewConnectionService(connectionRepo *connectionRepositories.ConnectionRepository, userDeliveryRepo *userDeliveryRepositories.UserDeliveryRepository, webviewRepo *webviewRepositories.WebViewRepository) *ConnectionService {
+func NewConnectionService(connectionRepo *connectionRepositories.ConnectionRepository, userDeliveryRepo *userDeliveryRepositories.UserDeliveryRepository, webviewRepo *webviewRepositories.WebViewRepository, auditService *auditServices.AuditLogService) *ConnectionService {
 	return &ConnectionService{
 		connectionRepo:   connectionRepo,
 		userDeliveryRepo: userDeliveryRepo,
 		webviewRepo:      webviewRepo,
+		auditService:     auditService,
 	}
 }
 
-func (service *ConnectionService) CreateConnection(ctx context.Context, req dto.CreateConnection) (primitive.ObjectID, error) {
-	if req.WebviewServerId != "" {
-		webviewExists, err := service.webviewRepo.IsWebviewExistsByID(ctx, req.WebviewServerId)
-		if err != nil {
-			return primitive.NilObjectID, err
-		}
-		if !webviewExists {
-			return primitive.NilObjectID, errors.New("webview server does not exist")
-		}
+func (service *ConnectionService) CreateConnection(ctx context.Context, req dto.CreateConnection) (domain.CreateConnection, error) {
+	webview, err := service.webviewRepo.GetWebviewByID(ctx, req.WebviewServerId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.CreateConnection{}, helpers.NotFoundError("webview server with id '%s' does not exist", req.WebviewServerId)
+	}
+	if err != nil {
+		return domain.CreateConnection{}, err
 	}
 
-	if req.UserDeliveryServerId != "" {
-		userDeliveryExists, err := service.userDeliveryRepo.IsUserDeliveryExistsByID(ctx, req.UserDeliveryServerId)
-		if err != nil {
-			return primitive.NilObjectID, err
-		}
-		if !userDeliveryExists {
-			return primitive.NilObjectID, errors.New("user delivery server does not exist")
-		}
+	userDelivery, err := service.userDeliveryRepo.GetUserDeliveryByID(ctx, req.UserDeliveryServerId)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.CreateConnection{}, helpers.NotFoundError("user delivery server with id '%s' does not exist", req.UserDeliveryServerId)
+	}
+	if err != nil {
+		return domain.CreateConnection{}, err
+	}
+
+	// The connection belongs to the tenant owning both servers, not to the caller, so a
+	// super admin creating it on a tenant's behalf leaves it manageable by that tenant
+	if webview.OwnerID != userDelivery.OwnerID {
+		return domain.CreateConnection{}, helpers.ValidationError("webview server and user delivery server belong to different owners")
 	}
 
-	exists, err := service.connectionRepo.IsHavingSameConnection(req.UserDeliveryServerId, req.WebviewServerId)
+	exists, err := service.connectionRepo.IsHavingSameConnection(ctx, req.UserDeliveryServerId, req.WebviewServerId)
 	if err != nil {
-		return primitive.NilObjectID, err
+		return domain.CreateConnection{}, err
 	}
 	if exists {
-		return primitive.NilObjectID, errors.New("connection already exists")
+		return domain.CreateConnection{}, helpers.ConflictError("connection already exists")
 	}
 
 	webviewServerApiKey, err := generateRandomAPIKey()
 	if err != nil {
-		return primitive.NilObjectID, err
+		return domain.CreateConnection{}, err
 	}
 	userDeliveryServerApiKey, err := generateRandomAPIKey()
 	if err != nil {
-		return primitive.NilObjectID, err
+		return domain.CreateConnection{}, err
+	}
+
+	webviewApiKey, err := models.NewHashedApiKey(webviewServerApiKey)
+	if err != nil {
+		return domain.CreateConnection{}, err
+	}
+	userDeliveryApiKey, err := models.NewEncryptedApiKey(userDeliveryServerApiKey)
+	if err != nil {
+		return domain.CreateConnection{}, err
 	}
 
 	objectID := primitive.NewObjectID()
@@ -78,18 +96,28 @@ func (service *ConnectionService) CreateConnection(ctx context.Context, req dto.
 		CreatedAt:                    time.Now(),
 		UpdatedAt:                    time.Now(),
 		Status:                       "inactive",
-		WebviewServerApiKey:          webviewServerApiKey,
-		UserDeliveryServerApiKey:     userDeliveryServerApiKey,
+		WebviewServerApiKey:          webviewApiKey,
+		UserDeliveryServerApiKey:     userDeliveryApiKey,
 		WebviewServerId:              req.WebviewServerId,
 		UserDeliveryServerId:         req.UserDeliveryServerId,
 		UserDeliveryServerWebHookUrl: req.UserDeliveryServerWebHookUrl,
+		MaxDeliveryAttempts:          req.MaxDeliveryAttempts,
+		RateLimitPerSecond:           req.RateLimitPerSecond,
+		RateLimitBurst:               req.RateLimitBurst,
+		OwnerID:                      webview.OwnerID,
 	}
-	err = service.connectionRepo.CreateConnection(newConnection)
+	err = service.connectionRepo.CreateConnection(ctx, newConnection)
 	if err != nil {
-		return primitive.NilObjectID, err
+		return domain.CreateConnection{}, err
 	}
 
-	return objectID, nil
+	service.auditService.Record(ctx, auditModels.ActionCreate, auditModels.TargetConnection, newConnection.ID, nil, newConnection)
+
+	return domain.CreateConnection{
+		ID:                       objectID.Hex(),
+		WebviewServerApiKey:      webviewServerApiKey,
+		UserDeliveryServerApiKey: userDeliveryServerApiKey,
+	}, nil
 }
 
 func generateRandomAPIKey() (string, error) {
@@ -102,9 +130,9 @@ func generateRandomAPIKey() (string, error) {
 }
 
 func (service *ConnectionService) GetConnections(ctx context.Context, req dto.GetConnections) (domain.ConnectionResponse, error) {
-	cacheKey := fmt.Sprintf("connections:%s:%s:%s:%d:%s", req.UserDeliveryServerId, req.WebviewServerId, req.Status, req.Limit, req.PageToken)
+	cacheKey := fmt.Sprintf("connections:%s:%s:%s:%s:%d:%s", helpers.TenantCacheKey(ctx), req.UserDeliveryServerId, req.WebviewServerId, req.Status, req.Limit, req.PageToken)
 
-	cachedData, err := helpers.GetCache(cacheKey)
+	cachedData, err := helpers.GetCache(ctx, cacheKey)
 	if err == nil {
 		var cachedResponse domain.ConnectionResponse
 		if jsonErr := json.Unmarshal([]byte(cachedData), &cachedResponse); jsonErr == nil {
@@ -118,7 +146,7 @@ func (service *ConnectionService) GetConnections(ctx context.Context, req dto.Ge
 			return domain.ConnectionResponse{}, err
 		}
 		if !webviewExists {
-			return domain.ConnectionResponse{}, errors.New("webview server does not exist")
+			return domain.ConnectionResponse{}, helpers.NotFoundError("webview server with id '%s' does not exist", req.WebviewServerId)
 		}
 	}
 
@@ -128,7 +156,7 @@ func (service *ConnectionService) GetConnections(ctx context.Context, req dto.Ge
 			return domain.ConnectionResponse{}, err
 		}
 		if !userDeliveryExists {
-			return domain.ConnectionResponse{}, errors.New("user delivery server does not exist")
+			return domain.ConnectionResponse{}, helpers.NotFoundError("user delivery server with id '%s' does not exist", req.UserDeliveryServerId)
 		}
 	}
 
@@ -147,84 +175,210 @@ func (service *ConnectionService) GetConnections(ctx context.Context, req dto.Ge
 	}
 
 	jsonData, _ := json.Marshal(response)
-	_ = helpers.SetCache(cacheKey, string(jsonData))
+	_ = helpers.SetCache(ctx, cacheKey, string(jsonData))
 
 	return response, nil
 }
 
 func (service *ConnectionService) UpdateWebHookUrl(ctx context.Context, dto dto.UpdateUserDelivery) error {
-	exists, err := service.connectionRepo.IsHavingConnectionById(ctx, dto.ID)
+	connection, err := service.connectionRepo.GetConnectionByID(ctx, dto.ID)
+	if err != nil {
+		return err
+	}
+	if connection.ID == "" {
+		return helpers.NotFoundError("connection with ID %s does not exist", dto.ID)
+	}
+
+	if connection.UserDeliveryServerWebHookUrl == dto.UserDeliveryServerWebHookUrl {
+		return nil
+	}
+
+	if err := service.connectionRepo.UpdateUserDeliveryHookUrl(ctx, dto.ID, dto.UserDeliveryServerWebHookUrl); err != nil {
+		return err
+	}
+
+	after := connection
+	after.UserDeliveryServerWebHookUrl = dto.UserDeliveryServerWebHookUrl
+	after.WebHookVerified = false
+	after.WebHookVerifiedAt = time.Time{}
+	after.Status = models.StatusInactive
+	service.auditService.Record(ctx, auditModels.ActionUpdate, auditModels.TargetConnection, dto.ID, connection, after)
+	return nil
+}
+
+func (service *ConnectionService) UpdateDeliverySettings(ctx context.Context, dto dto.UpdateDeliverySettings) error {
+	connection, err := service.connectionRepo.GetConnectionByID(ctx, dto.ID)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		return fmt.Errorf("connection with ID %s does not exist", dto.ID)
+	if connection.ID == "" {
+		return helpers.NotFoundError("connection with ID %s does not exist", dto.ID)
 	}
 
-	return service.connectionRepo.UpdateUserDeliveryHookUrl(ctx, dto.ID, dto.UserDeliveryServerWebHookUrl)
+	after := connection
+	settings := bson.M{}
+	if dto.MaxDeliveryAttempts != nil {
+		settings["maxDeliveryAttempts"] = *dto.MaxDeliveryAttempts
+		after.MaxDeliveryAttempts = *dto.MaxDeliveryAttempts
+	}
+	if dto.RateLimitPerSecond != nil {
+		settings["rateLimitPerSecond"] = *dto.RateLimitPerSecond
+		after.RateLimitPerSecond = *dto.RateLimitPerSecond
+	}
+	if dto.RateLimitBurst != nil {
+		settings["rateLimitBurst"] = *dto.RateLimitBurst
+		after.RateLimitBurst = *dto.RateLimitBurst
+	}
+
+	if err := service.connectionRepo.UpdateDeliverySettings(ctx, dto.ID, settings); err != nil {
+		return err
+	}
+
+	service.auditService.Record(ctx, auditModels.ActionUpdate, auditModels.TargetConnection, dto.ID, connection, after)
+	return nil
+}
+
+var ErrKeyRotationConflict = helpers.ConflictError("connection keys changed during rotation, please retry")
+
+// RotateKeys issues new API keys while the previous ones stay valid for the configured
+// grace period, so both servers can switch over without dropping requests.
+func (service *ConnectionService) RotateKeys(ctx context.Context, req dto.RotateKeys) (domain.RotateKeys, error) {
+	connection, err := service.connectionRepo.GetConnectionByID(ctx, req.ID)
+	if err != nil {
+		return domain.RotateKeys{}, err
+	}
+	if connection.ID == "" {
+		return domain.RotateKeys{}, helpers.NotFoundError("connection with ID %s does not exist", req.ID)
+	}
+
+	previousKeyExpiresAt := time.Now().Add(config.ApiKeyConfig.RotationGracePeriod)
+	response := domain.RotateKeys{
+		ID:                   connection.ID,
+		PreviousKeyExpiresAt: previousKeyExpiresAt,
+	}
+	after := connection
+	current := bson.M{}
+	set := bson.M{}
+
+	if req.Key == models.ApiKeyWebview || req.Key == models.ApiKeyBoth {
+		webviewServerApiKey, err := generateRandomAPIKey()
+		if err != nil {
+			return domain.RotateKeys{}, err
+		}
+		webviewApiKey, err := models.NewHashedApiKey(webviewServerApiKey)
+		if err != nil {
+			return domain.RotateKeys{}, err
+		}
+
+		previous := connection.WebviewServerApiKey
+		previous.ExpiresAt = previousKeyExpiresAt
+		current[models.WebviewApiKeyField+".hash"] = connection.WebviewServerApiKey.Hash
+		set[models.WebviewApiKeyField] = webviewApiKey
+		set[models.PreviousWebviewApiKeyField] = previous
+		after.WebviewServerApiKey = webviewApiKey
+		after.PreviousWebviewServerApiKey = &previous
+		response.WebviewServerApiKey = webviewServerApiKey
+	}
+
+	if req.Key == models.ApiKeyUserDelivery || req.Key == models.ApiKeyBoth {
+		userDeliveryServerApiKey, err := generateRandomAPIKey()
+		if err != nil {
+			return domain.RotateKeys{}, err
+		}
+		userDeliveryApiKey, err := models.NewEncryptedApiKey(userDeliveryServerApiKey)
+		if err != nil {
+			return domain.RotateKeys{}, err
+		}
+
+		previous := connection.UserDeliveryServerApiKey
+		previous.ExpiresAt = previousKeyExpiresAt
+		current[models.UserDeliveryApiKeyField+".ciphertext"] = connection.UserDeliveryServerApiKey.Ciphertext
+		set[models.UserDeliveryApiKeyField] = userDeliveryApiKey
+		set[models.PreviousUserDeliveryApiKeyField] = previous
+		after.UserDeliveryServerApiKey = userDeliveryApiKey
+		after.PreviousUserDeliveryServerApiKey = &previous
+		response.UserDeliveryServerApiKey = userDeliveryServerApiKey
+	}
+
+	rotated, err := service.connectionRepo.RotateKeys(ctx, connection.ID, current, set)
+	if err != nil {
+		return domain.RotateKeys{}, err
+	}
+	if !rotated {
+		return domain.RotateKeys{}, ErrKeyRotationConflict
+	}
+
+	service.auditService.Record(ctx, auditModels.ActionRotateKeys, auditModels.TargetConnection, connection.ID, connection, after)
+	return response, nil
 }
 
 func (s *ConnectionService) ChangeConnectionStatus(ctx context.Context, req dto.ChangeConnectionStatus) (domain.ConnectionResponse, error) {
+	// Suspend chỉ do delivery worker thực hiện (kèm suspendedReason/suspendedAt)
+	if req.Status == models.StatusSuspended {
+		return domain.ConnectionResponse{}, helpers.ValidationError("status '%s' cannot be set manually, connections are suspended by the delivery worker", models.StatusSuspended)
+	}
+
 	connection, err := s.connectionRepo.GetConnectionByID(ctx, req.ID)
 	if err != nil {
-		return domain.ConnectionResponse{
-			Message: "Connection not found, no status change applied",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.ConnectionResponse{}, err
+	}
+	if connection.ID == "" {
+		return domain.ConnectionResponse{}, helpers.NotFoundError("connection with ID %s does not exist", req.ID)
 	}
 
 	if connection.Status == req.Status {
-		return domain.ConnectionResponse{
-			Message: "Connection status is already the same as the requested status",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("connection with id '%s' already has the requested status '%s'", req.ID, req.Status)
+		return domain.ConnectionResponse{}, helpers.ValidationError("connection with id '%s' already has the requested status '%s'", req.ID, req.Status)
 	}
 
 	if req.Status == "active" {
 		isWebviewActive, err := s.webviewRepo.IsWebviewActive(ctx, connection.WebviewServerId)
 		if err != nil {
-			return domain.ConnectionResponse{
-				Message: "Error checking webview server status",
-				Code:    500,
-				Data:    nil,
-			}, err
+			return domain.ConnectionResponse{}, err
 		}
 		if !isWebviewActive {
-			return domain.ConnectionResponse{
-				Message: "Webview server is not active, cannot change status to active",
-				Code:    400,
-				Data:    nil,
-			}, fmt.Errorf("webview server with id '%s' is not active", connection.WebviewServerId)
+			return domain.ConnectionResponse{}, helpers.ValidationError("webview server with id '%s' is not active", connection.WebviewServerId)
 		}
 
 		isUserDeliveryActive, err := s.userDeliveryRepo.IsUserDeliveryActive(ctx, connection.UserDeliveryServerId)
 		if err != nil {
-			return domain.ConnectionResponse{
-				Message: "Error checking user delivery server status",
-				Code:    500,
-				Data:    nil,
-			}, err
+			return domain.ConnectionResponse{}, err
 		}
 		if !isUserDeliveryActive {
-			return domain.ConnectionResponse{
-				Message: "User delivery server is not active, cannot change status to active",
-				Code:    400,
-				Data:    nil,
-			}, fmt.Errorf("user delivery server with id '%s' is not active", connection.UserDeliveryServerId)
+			return domain.ConnectionResponse{}, helpers.ValidationError("user delivery server with id '%s' is not active", connection.UserDeliveryServerId)
+		}
+
+		if !connection.WebHookVerified {
+			if err := verifyWebHookUrl(ctx, connection); err != nil {
+				return domain.ConnectionResponse{}, err
+			}
+
+			verified, err := s.connectionRepo.MarkWebHookVerified(ctx, connection.ID, connection.UserDeliveryServerWebHookUrl)
+			if err != nil {
+				return domain.ConnectionResponse{}, err
+			}
+			if !verified {
+				return domain.ConnectionResponse{}, helpers.ConflictError("webhook URL of connection '%s' changed during verification, please retry", connection.ID)
+			}
 		}
 	}
 
-	objectID, updateErr := s.connectionRepo.ChangeConnectionStatus(ctx, req.ID, req.Status)
-	if updateErr != nil {
-		return domain.ConnectionResponse{
-			Message: "failed to update Connection status",
-			Code:    500,
-			Data:    nil,
-		}, updateErr
+	objectID, err := s.connectionRepo.ChangeConnectionStatus(ctx, req.ID, req.Status)
+	if err != nil {
+		return domain.ConnectionResponse{}, err
 	}
 
+	if req.Status == models.StatusActive {
+		// Resuming a suspended connection starts from a closed circuit
+		_ = helpers.CircuitRecordSuccess(ctx, helpers.CircuitBreakerKey(req.ID))
+	}
+
+	after := connection
+	after.Status = req.Status
+	after.WebHookVerified = after.WebHookVerified || req.Status == models.StatusActive
+	after.SuspendedReason = ""
+	after.SuspendedAt = time.Time{}
+	s.auditService.Record(ctx, auditModels.ActionChangeStatus, auditModels.TargetConnection, req.ID, connection, after)
+
 	return domain.ConnectionResponse{
 		Message: "success",
 		Code:    200,
@@ -233,13 +387,18 @@ func (s *ConnectionService) ChangeConnectionStatus(ctx context.Context, req dto.
 }
 
 func (service *ConnectionService) DeleteConnection(ctx context.Context, dto dto.DeleteConnection) error {
-	exists, err := service.connectionRepo.IsHavingConnectionById(ctx, dto.ID)
+	connection, err := service.connectionRepo.GetConnectionByID(ctx, dto.ID)
 	if err != nil {
 		return err
 	}
-	if !exists {
-		return fmt.Errorf("connection with ID %s does not exist", dto.ID)
+	if connection.ID == "" {
+		return helpers.NotFoundError("connection with ID %s does not exist", dto.ID)
+	}
+
+	if err := service.connectionRepo.DeleteConnection(ctx, dto.ID); err != nil {
+		return err
 	}
 
-	return service.connectionRepo.DeleteConnection(ctx, dto.ID)
+	service.auditService.Record(ctx, auditModels.ActionDelete, auditModels.TargetConnection, dto.ID, connection, nil)
+	return nil
 }
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"notification-server/helpers"
+	"notification-server/modules/connection/models"
+	"time"
+)
+
+const webHookVerificationType = "url_verification"
+
+var ErrWebHookVerificationFailed = helpers.PreconditionFailedError("webhook URL verification failed")
+
+type webHookChallenge struct {
+	Type         string `json:"type"`
+	ConnectionId string `json:"connectionId"`
+	Challenge    string `json:"challenge"`
+}
+
+// verifyWebHookUrl proves that whoever runs the webhook URL is willing to receive our
+// notifications: the receiver must answer a signed challenge request by echoing the
+// challenge token back as {"challenge": "<token>"}.
+func verifyWebHookUrl(ctx context.Context, connection models.Connection) error {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return err
+	}
+
+	challenge := webHookChallenge{
+		Type:         webHookVerificationType,
+		ConnectionId: connection.ID,
+		Challenge:    hex.EncodeToString(token),
+	}
+
+	body, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	secrets, err := connection.SigningSecrets(time.Now())
+	if err != nil {
+		return err
+	}
+	headers := map[string]string{
+		helpers.SignatureHeader: helpers.SignPayload(secrets, body, time.Now()),
+	}
+
+	result, err := helpers.PostWebhook(ctx, connection.UserDeliveryServerWebHookUrl, body, headers)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrWebHookVerificationFailed, err)
+	}
+	if result.StatusCode < 200 || result.StatusCode >= 300 {
+		return fmt.Errorf("%w: receiver responded with status %d", ErrWebHookVerificationFailed, result.StatusCode)
+	}
+
+	var answer webHookChallenge
+	if err := json.Unmarshal([]byte(result.Body), &answer); err != nil || answer.Challenge != challenge.Challenge {
+		return fmt.Errorf("%w: receiver did not echo the challenge token", ErrWebHookVerificationFailed)
+	}
+
+	return nil
+}
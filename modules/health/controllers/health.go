@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"net/http"
+	"notification-server/modules/health/domain"
+	"notification-server/modules/health/services"
+
+	"github.com/labstack/echo/v4"
+)
+
+type HealthController struct {
+	service *services.HealthService
+}
+
+func NewHealthController(service *services.HealthService) *HealthController {
+	return &HealthController{service: service}
+}
+
+// Liveness only tells the orchestrator the process is serving requests; a dependency
+// outage must not get the pod restarted.
+func (c *HealthController) Liveness(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, map[string]string{"status": domain.StatusOK})
+}
+
+func (c *HealthController) Readiness(ctx echo.Context) error {
+	readiness := c.service.Readiness(ctx.Request().Context())
+	if readiness.Status != domain.StatusOK {
+		return ctx.JSON(http.StatusServiceUnavailable, readiness)
+	}
+	return ctx.JSON(http.StatusOK, readiness)
+}
@@ -0,0 +1,18 @@
+package domain
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+type DependencyStatus struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Running  *int   `json:"running,omitempty"`
+	Expected *int   `json:"expected,omitempty"`
+}
+
+type Readiness struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"notification-server/config"
+	"notification-server/helpers"
+	"notification-server/modules/health/domain"
+	"time"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+type WorkerPool interface {
+	Running() int
+	Size() int
+}
+
+type HealthService struct {
+	deliveryPool WorkerPool
+}
+
+func NewHealthService(deliveryPool WorkerPool) *HealthService {
+	return &HealthService{deliveryPool: deliveryPool}
+}
+
+// Readiness checks every dependency rather than stopping at the first failure, so the
+// response shows everything that is wrong at once.
+func (s *HealthService) Readiness(ctx context.Context) domain.Readiness {
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	checks := map[string]domain.DependencyStatus{
+		"mongodb":         dependencyStatus(ctx, "mongodb", config.MongoDBClient.Ping(ctx, nil)),
+		"redis":           dependencyStatus(ctx, "redis", config.RedisClient.WithContext(ctx).Ping().Err()),
+		"deliveryWorkers": s.workerStatus(),
+	}
+
+	status := domain.StatusOK
+	for _, check := range checks {
+		if check.Status != domain.StatusOK {
+			status = domain.StatusUnavailable
+		}
+	}
+
+	return domain.Readiness{Status: status, Checks: checks}
+}
+
+func (s *HealthService) workerStatus() domain.DependencyStatus {
+	running := s.deliveryPool.Running()
+	expected := s.deliveryPool.Size()
+
+	check := domain.DependencyStatus{Status: domain.StatusOK, Running: &running, Expected: &expected}
+	if running < expected {
+		check.Status = domain.StatusUnavailable
+		check.Error = fmt.Sprintf("%d of %d delivery workers running", running, expected)
+	}
+	return check
+}
+
+// dependencyStatus không trả lỗi gốc vì /readyz không cần xác thực; chi tiết chỉ ghi vào log.
+func dependencyStatus(ctx context.Context, name string, err error) domain.DependencyStatus {
+	if err != nil {
+		helpers.Logger(ctx).Error("Readiness check failed", "dependency", name, "error", err)
+		return domain.DependencyStatus{Status: domain.StatusUnavailable, Error: "unavailable"}
+	}
+	return domain.DependencyStatus{Status: domain.StatusOK}
+}
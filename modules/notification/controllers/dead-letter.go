@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+	dto "notification-server/modules/notification/dtos"
+	"notification-server/modules/notification/services"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultDeadLetterPageSize = 20
+	maxDeadLetterPageSize     = 100
+)
+
+type DeadLetterController struct {
+	service *services.DeadLetterService
+}
+
+func NewDeadLetterController(service *services.DeadLetterService) *DeadLetterController {
+	return &DeadLetterController{service: service}
+}
+
+func (c *DeadLetterController) GetDeadLetters(ctx echo.Context) error {
+	var query dto.GetDeadLetters
+
+	if err := ctx.Bind(&query); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	query.ConnectionId = strings.TrimSpace(ctx.Param("id"))
+	if query.ConnectionId == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "ID cannot be empty"})
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultDeadLetterPageSize
+	}
+	if query.Limit > maxDeadLetterPageSize {
+		query.Limit = maxDeadLetterPageSize
+	}
+
+	response, err := c.service.GetDeadLetters(ctx.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *DeadLetterController) ReplayDeadLetter(ctx echo.Context) error {
+	req := dto.ReplayDeadLetter{
+		ConnectionId: strings.TrimSpace(ctx.Param("id")),
+		ID:           strings.TrimSpace(ctx.Param("deadLetterId")),
+	}
+
+	if req.ConnectionId == "" || req.ID == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "id and deadLetterId are required"})
+	}
+
+	response, err := c.service.ReplayDeadLetter(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *DeadLetterController) ReplayDeadLetters(ctx echo.Context) error {
+	var req dto.ReplayDeadLetters
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+
+	req.ConnectionId = strings.TrimSpace(ctx.Param("id"))
+	if req.ConnectionId == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "ID cannot be empty"})
+	}
+
+	response, err := c.service.ReplayDeadLetters(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	dto "notification-server/modules/notification/dtos"
+	"notification-server/modules/notification/models"
+	"notification-server/modules/notification/services"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultDeliveryAttemptPageSize = 50
+	maxDeliveryAttemptPageSize     = 200
+)
+
+type DeliveryAttemptController struct {
+	service *services.DeliveryAttemptService
+}
+
+func NewDeliveryAttemptController(service *services.DeliveryAttemptService) *DeliveryAttemptController {
+	return &DeliveryAttemptController{service: service}
+}
+
+func (c *DeliveryAttemptController) GetDeliveryAttempts(ctx echo.Context) error {
+	var query dto.GetDeliveryAttempts
+
+	if err := ctx.Bind(&query); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	query.ConnectionId = strings.TrimSpace(ctx.Param("id"))
+	if query.ConnectionId == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "ID cannot be empty"})
+	}
+
+	if query.Outcome != "" && !models.IsValidOutcome(query.Outcome) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid outcome type"})
+	}
+
+	if from := ctx.QueryParam("from"); from != "" {
+		parsed, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "from must be an RFC 3339 timestamp"})
+		}
+		query.From = parsed
+	}
+	if to := ctx.QueryParam("to"); to != "" {
+		parsed, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "to must be an RFC 3339 timestamp"})
+		}
+		query.To = parsed
+	}
+	if !query.From.IsZero() && !query.To.IsZero() && query.To.Before(query.From) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "to must not be before from"})
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultDeliveryAttemptPageSize
+	}
+	if query.Limit > maxDeliveryAttemptPageSize {
+		query.Limit = maxDeliveryAttemptPageSize
+	}
+
+	response, err := c.service.GetDeliveryAttempts(ctx.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
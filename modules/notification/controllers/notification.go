@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"notification-server/config"
+	connectionModels "notification-server/modules/connection/models"
+	dto "notification-server/modules/notification/dtos"
+	"notification-server/modules/notification/services"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+type NotificationController struct {
+	service *services.NotificationService
+}
+
+func NewNotificationController(service *services.NotificationService) *NotificationController {
+	return &NotificationController{service: service}
+}
+
+func (c *NotificationController) SendNotification(ctx echo.Context) error {
+	connection, ok := ctx.Get("connection").(connectionModels.Connection)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "connection not resolved"})
+	}
+
+	var req dto.SendNotification
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format, deliverAt must be an RFC 3339 timestamp"})
+	}
+
+	if len(req.Payload) == 0 || string(req.Payload) == "null" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "payload is required"})
+	}
+
+	if req.DeliverAt.After(time.Now().Add(config.DeliveryConfig.MaxScheduleAhead)) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "deliverAt is too far in the future"})
+	}
+
+	req.IdempotencyKey = strings.TrimSpace(ctx.Request().Header.Get(IdempotencyKeyHeader))
+	if len(req.IdempotencyKey) > maxIdempotencyKeyLength {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Idempotency-Key must be at most 255 characters"})
+	}
+
+	response, replayed, err := c.service.SendNotification(ctx.Request().Context(), connection, req)
+	if err != nil {
+		if errors.Is(err, services.ErrIdempotencyKeyReused) {
+			return ctx.JSON(http.StatusUnprocessableEntity, map[string]string{"error": err.Error()})
+		}
+		return err
+	}
+
+	if replayed {
+		ctx.Response().Header().Set(IdempotentReplayedHeader, "true")
+	}
+
+	return ctx.JSON(http.StatusAccepted, response)
+}
+
+func (c *NotificationController) CancelNotification(ctx echo.Context) error {
+	connection, ok := ctx.Get("connection").(connectionModels.Connection)
+	if !ok {
+		return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": "connection not resolved"})
+	}
+
+	req := dto.CancelNotification{ID: strings.TrimSpace(ctx.Param("id"))}
+	if req.ID == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+
+	response, err := c.service.CancelNotification(ctx.Request().Context(), connection, req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
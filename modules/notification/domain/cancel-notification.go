@@ -0,0 +1,6 @@
+package domain
+
+type CancelNotification struct {
+	ID        string `json:"id"`
+	Cancelled int64  `json:"cancelled"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "notification-server/modules/notification/models"
+
+type GetDeadLetters struct {
+	List          []models.DeadLetter `json:"list"`
+	NextPageToken string              `json:"nextPageToken"`
+}
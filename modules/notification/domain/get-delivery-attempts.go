@@ -0,0 +1,8 @@
+package domain
+
+import "notification-server/modules/notification/models"
+
+type GetDeliveryAttempts struct {
+	List          []models.DeliveryAttempt `json:"list"`
+	NextPageToken string                   `json:"nextPageToken"`
+}
@@ -0,0 +1,9 @@
+package domain
+
+import "time"
+
+type QueueStats struct {
+	ConnectionId string    `bson:"_id" json:"connectionId"`
+	Depth        int       `bson:"depth" json:"depth"`
+	OldestAt     time.Time `bson:"oldestAt" json:"oldestAt"`
+}
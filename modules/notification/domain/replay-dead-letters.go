@@ -0,0 +1,6 @@
+package domain
+
+type ReplayDeadLetters struct {
+	Replayed []string          `json:"replayed"`
+	Failed   map[string]string `json:"failed"`
+}
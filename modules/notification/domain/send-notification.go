@@ -0,0 +1,12 @@
+package domain
+
+type SendNotification struct {
+	ID           string `json:"id"`
+	ConnectionId string `json:"connectionId"`
+	Status       string `json:"status"`
+}
+
+type BroadcastNotification struct {
+	BroadcastId   string             `json:"broadcastId"`
+	Notifications []SendNotification `json:"notifications"`
+}
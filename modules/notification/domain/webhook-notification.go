@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookNotification struct {
+	ID              string          `json:"id"`
+	ConnectionId    string          `json:"connectionId"`
+	WebviewServerId string          `json:"webviewServerId"`
+	BroadcastId     string          `json:"broadcastId,omitempty"`
+	Payload         json.RawMessage `json:"payload"`
+	CreatedAt       time.Time       `json:"createdAt"`
+	Attempt         int             `json:"attempt"`
+}
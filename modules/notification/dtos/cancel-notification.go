@@ -0,0 +1,5 @@
+package dto
+
+type CancelNotification struct {
+	ID string `json:"id"`
+}
@@ -0,0 +1,7 @@
+package dto
+
+type GetDeadLetters struct {
+	ConnectionId string `json:"connectionId"`
+	Limit        int    `query:"limit"`
+	PageToken    string `query:"pageToken"`
+}
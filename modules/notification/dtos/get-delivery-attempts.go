@@ -0,0 +1,12 @@
+package dto
+
+import "time"
+
+type GetDeliveryAttempts struct {
+	ConnectionId string    `json:"connectionId"`
+	Outcome      string    `query:"outcome"`
+	From         time.Time `json:"-"`
+	To           time.Time `json:"-"`
+	Limit        int       `query:"limit"`
+	PageToken    string    `query:"pageToken"`
+}
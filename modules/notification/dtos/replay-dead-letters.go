@@ -0,0 +1,11 @@
+package dto
+
+type ReplayDeadLetter struct {
+	ConnectionId string `json:"connectionId"`
+	ID           string `json:"id"`
+}
+
+type ReplayDeadLetters struct {
+	ConnectionId string   `json:"connectionId"`
+	IDs          []string `json:"ids"`
+}
@@ -0,0 +1,13 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type SendNotification struct {
+	Payload        json.RawMessage `json:"payload"`
+	Broadcast      bool            `json:"broadcast"`
+	DeliverAt      time.Time       `json:"deliverAt"`
+	IdempotencyKey string          `json:"-"`
+}
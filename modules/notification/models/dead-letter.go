@@ -0,0 +1,19 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type DeadLetter struct {
+	ID                    string          `bson:"_id,omitempty" json:"_id"`
+	CreatedAt             time.Time       `bson:"createdAt" json:"createdAt"`
+	NotificationCreatedAt time.Time       `bson:"notificationCreatedAt" json:"notificationCreatedAt"`
+	ConnectionId          string          `bson:"connectionId" json:"connectionId"`
+	WebviewServerId       string          `bson:"webviewServerId" json:"webviewServerId"`
+	BroadcastId           string          `bson:"broadcastId,omitempty" json:"broadcastId,omitempty"`
+	Payload               json.RawMessage `bson:"payload" json:"payload"`
+	Attempts              int             `bson:"attempts" json:"attempts"`
+	LastStatusCode        int             `bson:"lastStatusCode,omitempty" json:"lastStatusCode,omitempty"`
+	LastError             string          `bson:"lastError" json:"lastError"`
+}
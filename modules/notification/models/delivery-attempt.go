@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeRetrying  = "retrying"
+	OutcomeFailed    = "failed"
+)
+
+func IsValidOutcome(outcome string) bool {
+	switch outcome {
+	case OutcomeSucceeded, OutcomeRetrying, OutcomeFailed:
+		return true
+	}
+	return false
+}
+
+type DeliveryAttempt struct {
+	ID             string    `bson:"_id,omitempty" json:"_id"`
+	CreatedAt      time.Time `bson:"createdAt" json:"createdAt"`
+	NotificationId string    `bson:"notificationId" json:"notificationId"`
+	ConnectionId   string    `bson:"connectionId" json:"connectionId"`
+	Attempt        int       `bson:"attempt" json:"attempt"`
+	Outcome        string    `bson:"outcome" json:"outcome"`
+	StatusCode     int       `bson:"statusCode,omitempty" json:"statusCode,omitempty"`
+	LatencyMs      int64     `bson:"latencyMs" json:"latencyMs"`
+	ResponseBody   string    `bson:"responseBody,omitempty" json:"responseBody,omitempty"`
+	Error          string    `bson:"error,omitempty" json:"error,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type Notification struct {
+	ID              string          `bson:"_id,omitempty" json:"_id"`
+	CreatedAt       time.Time       `bson:"createdAt" json:"createdAt"`
+	UpdatedAt       time.Time       `bson:"updatedAt" json:"updatedAt"`
+	Status          string          `bson:"status" json:"status"`
+	ConnectionId    string          `bson:"connectionId" json:"connectionId"`
+	WebviewServerId string          `bson:"webviewServerId" json:"webviewServerId"`
+	BroadcastId     string          `bson:"broadcastId,omitempty" json:"broadcastId,omitempty"`
+	Payload         json.RawMessage `bson:"payload" json:"payload"`
+	DeliverAt       time.Time       `bson:"deliverAt,omitempty" json:"deliverAt,omitempty"`
+	Attempts        int             `bson:"attempts" json:"attempts"`
+	NextAttemptAt   time.Time       `bson:"nextAttemptAt" json:"nextAttemptAt"`
+	LockedBy        string          `bson:"lockedBy,omitempty" json:"-"`
+	LockedUntil     time.Time       `bson:"lockedUntil,omitempty" json:"-"`
+	DeliveredAt     time.Time       `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
+	CancelledAt     time.Time       `bson:"cancelledAt,omitempty" json:"cancelledAt,omitempty"`
+	LastError       string          `bson:"lastError,omitempty" json:"lastError,omitempty"`
+}
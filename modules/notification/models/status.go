@@ -0,0 +1,18 @@
+package models
+
+const (
+	StatusScheduled = "scheduled"
+	StatusPending   = "pending"
+	StatusInFlight  = "in-flight"
+	StatusDelivered = "delivered"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+func IsValidStatus(status string) bool {
+	switch status {
+	case StatusScheduled, StatusPending, StatusInFlight, StatusDelivered, StatusFailed, StatusCancelled:
+		return true
+	}
+	return false
+}
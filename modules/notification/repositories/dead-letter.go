@@ -0,0 +1,126 @@
+package repositories
+
+import (
+	"context"
+	"notification-server/helpers"
+	"notification-server/modules/notification/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeadLetterRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDeadLetterRepository(db *mongo.Database) *DeadLetterRepository {
+	return &DeadLetterRepository{
+		collection: db.Collection("dead-letters"),
+	}
+}
+
+func (r *DeadLetterRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "connectionId", Value: 1}, {Key: "_id", Value: 1}},
+	})
+	return err
+}
+
+func (r *DeadLetterRepository) CreateDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error {
+	defer helpers.ObserveMongoOperation("dead-letter", "CreateDeadLetter", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(deadLetter.ID)
+	if err != nil {
+		return err
+	}
+
+	deadLetterDocument := bson.M{
+		"_id":                   objectID,
+		"createdAt":             deadLetter.CreatedAt,
+		"notificationCreatedAt": deadLetter.NotificationCreatedAt,
+		"connectionId":          deadLetter.ConnectionId,
+		"webviewServerId":       deadLetter.WebviewServerId,
+		"payload":               deadLetter.Payload,
+		"attempts":              deadLetter.Attempts,
+		"lastStatusCode":        deadLetter.LastStatusCode,
+		"lastError":             deadLetter.LastError,
+	}
+	if deadLetter.BroadcastId != "" {
+		deadLetterDocument["broadcastId"] = deadLetter.BroadcastId
+	}
+
+	_, err = r.collection.InsertOne(ctx, deadLetterDocument)
+	return err
+}
+
+func (r *DeadLetterRepository) GetDeadLetters(ctx context.Context, connectionId string, limit int, nextPageToken string) ([]models.DeadLetter, string, error) {
+	defer helpers.ObserveMongoOperation("dead-letter", "GetDeadLetters", time.Now())
+	var deadLetters []models.DeadLetter
+	filter := bson.M{"connectionId": connectionId}
+
+	if nextPageToken != "" {
+		tokenID, err := helpers.StringToObjectID(nextPageToken)
+		if err != nil {
+			return nil, "", helpers.ValidationError("invalid nextPageToken: %s", nextPageToken)
+		}
+		filter["_id"] = bson.M{"$gt": tokenID}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limitInt64 := int64(limit)
+	cursor, err := r.collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limitInt64,
+		Sort:  bson.M{"_id": 1},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var lastID string
+	for cursor.Next(ctx) {
+		var deadLetter models.DeadLetter
+		if err := cursor.Decode(&deadLetter); err != nil {
+			return nil, "", err
+		}
+
+		deadLetters = append(deadLetters, deadLetter)
+		lastID = deadLetter.ID
+	}
+
+	return deadLetters, lastID, nil
+}
+
+func (r *DeadLetterRepository) GetDeadLetterByID(ctx context.Context, connectionId string, id string) (models.DeadLetter, error) {
+	defer helpers.ObserveMongoOperation("dead-letter", "GetDeadLetterByID", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.DeadLetter{}, err
+	}
+
+	var deadLetter models.DeadLetter
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID, "connectionId": connectionId}).Decode(&deadLetter)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.DeadLetter{}, nil
+		}
+		return models.DeadLetter{}, err
+	}
+
+	return deadLetter, nil
+}
+
+func (r *DeadLetterRepository) DeleteDeadLetter(ctx context.Context, id string) error {
+	defer helpers.ObserveMongoOperation("dead-letter", "DeleteDeadLetter", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+	"notification-server/helpers"
+	"notification-server/modules/notification/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeliveryAttemptRepository struct {
+	collection *mongo.Collection
+}
+
+func NewDeliveryAttemptRepository(db *mongo.Database) *DeliveryAttemptRepository {
+	return &DeliveryAttemptRepository{
+		collection: db.Collection("delivery-attempts"),
+	}
+}
+
+func (r *DeliveryAttemptRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "connectionId", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "connectionId", Value: 1}, {Key: "outcome", Value: 1}, {Key: "_id", Value: 1}}},
+		{Keys: bson.D{{Key: "notificationId", Value: 1}}},
+	})
+	return err
+}
+
+func (r *DeliveryAttemptRepository) CreateDeliveryAttempt(ctx context.Context, attempt *models.DeliveryAttempt) error {
+	defer helpers.ObserveMongoOperation("delivery-attempt", "CreateDeliveryAttempt", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(attempt.ID)
+	if err != nil {
+		return err
+	}
+
+	attemptDocument := bson.M{
+		"_id":            objectID,
+		"createdAt":      attempt.CreatedAt,
+		"notificationId": attempt.NotificationId,
+		"connectionId":   attempt.ConnectionId,
+		"attempt":        attempt.Attempt,
+		"outcome":        attempt.Outcome,
+		"statusCode":     attempt.StatusCode,
+		"latencyMs":      attempt.LatencyMs,
+		"responseBody":   attempt.ResponseBody,
+		"error":          attempt.Error,
+	}
+
+	_, err = r.collection.InsertOne(ctx, attemptDocument)
+	return err
+}
+
+func (r *DeliveryAttemptRepository) GetDeliveryAttempts(ctx context.Context, connectionId string, outcome string, from time.Time, to time.Time, limit int, nextPageToken string) ([]models.DeliveryAttempt, string, error) {
+	defer helpers.ObserveMongoOperation("delivery-attempt", "GetDeliveryAttempts", time.Now())
+	var attempts []models.DeliveryAttempt
+	filter := bson.M{"connectionId": connectionId}
+
+	if outcome != "" {
+		filter["outcome"] = outcome
+	}
+
+	createdAt := bson.M{}
+	if !from.IsZero() {
+		createdAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		createdAt["$lte"] = to
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	if nextPageToken != "" {
+		tokenID, err := helpers.StringToObjectID(nextPageToken)
+		if err != nil {
+			return nil, "", helpers.ValidationError("invalid nextPageToken: %s", nextPageToken)
+		}
+		filter["_id"] = bson.M{"$gt": tokenID}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limitInt64 := int64(limit)
+	cursor, err := r.collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limitInt64,
+		Sort:  bson.M{"_id": 1},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var lastID string
+	for cursor.Next(ctx) {
+		var attempt models.DeliveryAttempt
+		if err := cursor.Decode(&attempt); err != nil {
+			return nil, "", err
+		}
+
+		attempts = append(attempts, attempt)
+		lastID = attempt.ID
+	}
+
+	return attempts, lastID, nil
+}
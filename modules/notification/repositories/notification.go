@@ -0,0 +1,376 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"notification-server/helpers"
+	"notification-server/modules/notification/domain"
+	"notification-server/modules/notification/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrLeaseLost = errors.New("notification lease is no longer held by this worker")
+
+type NotificationRepository struct {
+	collection *mongo.Collection
+	client     *mongo.Client
+}
+
+func NewNotificationRepository(db *mongo.Database, client *mongo.Client) *NotificationRepository {
+	return &NotificationRepository{
+		collection: db.Collection("notifications"),
+		client:     client,
+	}
+}
+
+func (r *NotificationRepository) StartSession(ctx context.Context) (mongo.Session, error) {
+	return r.client.StartSession()
+}
+
+func (r *NotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "nextAttemptAt", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "lockedUntil", Value: 1}}},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "deliverAt", Value: 1}}},
+		{Keys: bson.D{{Key: "connectionId", Value: 1}}},
+		{Keys: bson.D{{Key: "broadcastId", Value: 1}}, Options: options.Index().SetSparse(true)},
+	})
+	return err
+}
+
+func (r *NotificationRepository) CreateNotification(ctx context.Context, notification *models.Notification) error {
+	defer helpers.ObserveMongoOperation("notification", "CreateNotification", time.Now())
+	notificationDocument, err := toNotificationDocument(notification)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.InsertOne(ctx, notificationDocument)
+	return err
+}
+
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []models.Notification) error {
+	defer helpers.ObserveMongoOperation("notification", "CreateNotifications", time.Now())
+	documents := make([]interface{}, 0, len(notifications))
+	for i := range notifications {
+		notificationDocument, err := toNotificationDocument(&notifications[i])
+		if err != nil {
+			return err
+		}
+		documents = append(documents, notificationDocument)
+	}
+
+	// Một broadcast được ghi trọn vẹn hoặc không ghi gì, để client thử lại với cùng
+	// Idempotency-Key không tạo ra bản sao thứ hai cho các connection đã được ghi
+	session, err := r.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		return r.collection.InsertMany(sessCtx, documents)
+	})
+	return err
+}
+
+func toNotificationDocument(notification *models.Notification) (bson.M, error) {
+	objectID, err := primitive.ObjectIDFromHex(notification.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	notificationDocument := bson.M{
+		"_id":             objectID,
+		"createdAt":       notification.CreatedAt,
+		"updatedAt":       notification.UpdatedAt,
+		"status":          notification.Status,
+		"connectionId":    notification.ConnectionId,
+		"webviewServerId": notification.WebviewServerId,
+		"payload":         notification.Payload,
+		"attempts":        notification.Attempts,
+		"nextAttemptAt":   notification.NextAttemptAt,
+	}
+	if notification.BroadcastId != "" {
+		notificationDocument["broadcastId"] = notification.BroadcastId
+	}
+	if !notification.DeliverAt.IsZero() {
+		notificationDocument["deliverAt"] = notification.DeliverAt
+	}
+
+	return notificationDocument, nil
+}
+
+// ReleaseDueNotifications hands scheduled notifications whose deliverAt has passed to
+// the delivery workers. The status filter makes the transition idempotent, so every
+// replica can run it without coordinating with the others.
+func (r *NotificationRepository) ReleaseDueNotifications(ctx context.Context, now time.Time) (int64, error) {
+	defer helpers.ObserveMongoOperation("notification", "ReleaseDueNotifications", time.Now())
+	filter := bson.M{
+		"status":    models.StatusScheduled,
+		"deliverAt": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":    models.StatusPending,
+			"updatedAt": now,
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// GetQueueStats counts notifications waiting for delivery per connection, including
+// those a worker currently holds, together with the creation time of the oldest one.
+func (r *NotificationRepository) GetQueueStats(ctx context.Context) ([]domain.QueueStats, error) {
+	defer helpers.ObserveMongoOperation("notification", "GetQueueStats", time.Now())
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"status": bson.M{"$in": bson.A{models.StatusPending, models.StatusInFlight}}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      "$connectionId",
+			"depth":    bson.M{"$sum": 1},
+			"oldestAt": bson.M{"$min": "$createdAt"},
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var stats []domain.QueueStats
+	if err := cursor.All(ctx, &stats); err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// CancelNotifications cancels a notification, or every copy of a broadcast when id is a
+// broadcast ID, as long as no worker has picked it up yet.
+func (r *NotificationRepository) CancelNotifications(ctx context.Context, id string, webviewServerId string) (int64, error) {
+	defer helpers.ObserveMongoOperation("notification", "CancelNotifications", time.Now())
+	idFilter := bson.A{bson.M{"broadcastId": id}}
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		idFilter = append(idFilter, bson.M{"_id": objectID})
+	}
+
+	now := time.Now()
+	filter := bson.M{
+		"$or":             idFilter,
+		"webviewServerId": webviewServerId,
+		"status":          bson.M{"$in": bson.A{models.StatusScheduled, models.StatusPending}},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      models.StatusCancelled,
+			"cancelledAt": now,
+			"updatedAt":   now,
+		},
+	}
+
+	result, err := r.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+func (r *NotificationRepository) CountNotifications(ctx context.Context, id string, webviewServerId string) (int64, error) {
+	defer helpers.ObserveMongoOperation("notification", "CountNotifications", time.Now())
+	idFilter := bson.A{bson.M{"broadcastId": id}}
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		idFilter = append(idFilter, bson.M{"_id": objectID})
+	}
+
+	return r.collection.CountDocuments(ctx, bson.M{
+		"$or":             idFilter,
+		"webviewServerId": webviewServerId,
+	})
+}
+
+// ClaimNextNotification atomically hands one due notification to workerId. Items whose
+// lease expired (the owning worker crashed or was killed) are claimable again, so an
+// outbox shared by several replicas never has two workers holding the same item.
+func (r *NotificationRepository) ClaimNextNotification(ctx context.Context, workerId string, lease time.Duration) (models.Notification, error) {
+	defer helpers.ObserveMongoOperation("notification", "ClaimNextNotification", time.Now())
+	now := time.Now()
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": models.StatusPending, "nextAttemptAt": bson.M{"$lte": now}},
+			bson.M{"status": models.StatusInFlight, "lockedUntil": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":      models.StatusInFlight,
+			"lockedBy":    workerId,
+			"lockedUntil": now.Add(lease),
+			"updatedAt":   now,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.M{"nextAttemptAt": 1}).
+		SetReturnDocument(options.After)
+
+	var notification models.Notification
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&notification)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.Notification{}, nil
+		}
+		return models.Notification{}, err
+	}
+
+	return notification, nil
+}
+
+func (r *NotificationRepository) MarkDelivered(ctx context.Context, id string, workerId string, attempts int) error {
+	defer helpers.ObserveMongoOperation("notification", "MarkDelivered", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":      models.StatusDelivered,
+			"attempts":    attempts,
+			"deliveredAt": now,
+			"updatedAt":   now,
+		},
+		"$unset": bson.M{"lockedBy": "", "lockedUntil": "", "lastError": ""},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "lockedBy": workerId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkForRetry(ctx context.Context, id string, workerId string, attempts int, nextAttemptAt time.Time, lastError string) error {
+	defer helpers.ObserveMongoOperation("notification", "MarkForRetry", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":        models.StatusPending,
+			"attempts":      attempts,
+			"nextAttemptAt": nextAttemptAt,
+			"lastError":     lastError,
+			"updatedAt":     time.Now(),
+		},
+		"$unset": bson.M{"lockedBy": "", "lockedUntil": ""},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "lockedBy": workerId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// DeferNotification releases the lease without counting an attempt, for deliveries
+// that were held back before anything was sent.
+func (r *NotificationRepository) DeferNotification(ctx context.Context, id string, workerId string, nextAttemptAt time.Time) error {
+	defer helpers.ObserveMongoOperation("notification", "DeferNotification", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":        models.StatusPending,
+			"nextAttemptAt": nextAttemptAt,
+			"updatedAt":     time.Now(),
+		},
+		"$unset": bson.M{"lockedBy": "", "lockedUntil": ""},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "lockedBy": workerId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id string, workerId string, attempts int, lastError string) error {
+	defer helpers.ObserveMongoOperation("notification", "MarkFailed", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":    models.StatusFailed,
+			"attempts":  attempts,
+			"lastError": lastError,
+			"updatedAt": time.Now(),
+		},
+		"$unset": bson.M{"lockedBy": "", "lockedUntil": ""},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "lockedBy": workerId}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *NotificationRepository) RequeueNotification(ctx context.Context, id string) (bool, error) {
+	defer helpers.ObserveMongoOperation("notification", "RequeueNotification", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{
+			"status":        models.StatusPending,
+			"attempts":      0,
+			"nextAttemptAt": now,
+			"updatedAt":     now,
+		},
+		"$unset": bson.M{"lastError": ""},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": objectID, "status": models.StatusFailed}, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"notification-server/helpers"
+	connectionRepositories "notification-server/modules/connection/repositories"
+	"notification-server/modules/notification/domain"
+	dto "notification-server/modules/notification/dtos"
+	"notification-server/modules/notification/models"
+	"notification-server/modules/notification/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const maxBulkReplay = 1000
+
+type DeadLetterService struct {
+	repo             *repositories.DeadLetterRepository
+	notificationRepo *repositories.NotificationRepository
+	connectionRepo   *connectionRepositories.ConnectionRepository
+}
+
+func NewDeadLetterService(repo *repositories.DeadLetterRepository, notificationRepo *repositories.NotificationRepository, connectionRepo *connectionRepositories.ConnectionRepository) *DeadLetterService {
+	return &DeadLetterService{
+		repo:             repo,
+		notificationRepo: notificationRepo,
+		connectionRepo:   connectionRepo,
+	}
+}
+
+func (s *DeadLetterService) GetDeadLetters(ctx context.Context, req dto.GetDeadLetters) (domain.NotificationResponse, error) {
+	if err := s.ensureConnectionExists(ctx, req.ConnectionId); err != nil {
+		return domain.NotificationResponse{}, err
+	}
+
+	deadLetters, nextPageToken, err := s.repo.GetDeadLetters(ctx, req.ConnectionId, req.Limit, req.PageToken)
+	if err != nil {
+		return domain.NotificationResponse{}, err
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    200,
+		Data: domain.GetDeadLetters{
+			List:          deadLetters,
+			NextPageToken: nextPageToken,
+		},
+	}, nil
+}
+
+func (s *DeadLetterService) ReplayDeadLetter(ctx context.Context, req dto.ReplayDeadLetter) (domain.NotificationResponse, error) {
+	if err := s.ensureConnectionExists(ctx, req.ConnectionId); err != nil {
+		return domain.NotificationResponse{}, err
+	}
+
+	if err := s.replay(ctx, req.ConnectionId, req.ID); err != nil {
+		return domain.NotificationResponse{}, err
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    200,
+		Data: domain.ReplayDeadLetters{
+			Replayed: []string{req.ID},
+			Failed:   map[string]string{},
+		},
+	}, nil
+}
+
+func (s *DeadLetterService) ReplayDeadLetters(ctx context.Context, req dto.ReplayDeadLetters) (domain.NotificationResponse, error) {
+	if err := s.ensureConnectionExists(ctx, req.ConnectionId); err != nil {
+		return domain.NotificationResponse{}, err
+	}
+
+	ids := req.IDs
+	if len(ids) == 0 {
+		pageToken := ""
+		for len(ids) < maxBulkReplay {
+			deadLetters, lastID, err := s.repo.GetDeadLetters(ctx, req.ConnectionId, 100, pageToken)
+			if err != nil {
+				return domain.NotificationResponse{}, err
+			}
+			for _, deadLetter := range deadLetters {
+				ids = append(ids, deadLetter.ID)
+			}
+			if len(deadLetters) < 100 {
+				break
+			}
+			pageToken = lastID
+		}
+	}
+
+	if len(ids) > maxBulkReplay {
+		ids = ids[:maxBulkReplay]
+	}
+
+	result := domain.ReplayDeadLetters{
+		Replayed: []string{},
+		Failed:   map[string]string{},
+	}
+	for _, id := range ids {
+		if err := s.replay(ctx, req.ConnectionId, id); err != nil {
+			result.Failed[id] = err.Error()
+			continue
+		}
+		result.Replayed = append(result.Replayed, id)
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    200,
+		Data:    result,
+	}, nil
+}
+
+// replay puts the original notification back into the outbox under the same ID, so
+// receivers that de-duplicate on the notification ID still recognise it.
+func (s *DeadLetterService) replay(ctx context.Context, connectionId string, id string) error {
+	deadLetter, err := s.repo.GetDeadLetterByID(ctx, connectionId, id)
+	if err != nil {
+		return err
+	}
+	if deadLetter.ID == "" {
+		return helpers.NotFoundError("dead letter with ID %s does not exist", id)
+	}
+
+	session, err := s.notificationRepo.StartSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := s.repo.DeleteDeadLetter(sessCtx, deadLetter.ID); err != nil {
+			return nil, err
+		}
+
+		requeued, err := s.notificationRepo.RequeueNotification(sessCtx, deadLetter.ID)
+		if err != nil || requeued {
+			return nil, err
+		}
+
+		now := time.Now()
+		notification := models.Notification{
+			ID:              deadLetter.ID,
+			CreatedAt:       deadLetter.NotificationCreatedAt,
+			UpdatedAt:       now,
+			Status:          models.StatusPending,
+			ConnectionId:    deadLetter.ConnectionId,
+			WebviewServerId: deadLetter.WebviewServerId,
+			BroadcastId:     deadLetter.BroadcastId,
+			Payload:         deadLetter.Payload,
+			Attempts:        0,
+			NextAttemptAt:   now,
+		}
+		return nil, s.notificationRepo.CreateNotification(sessCtx, &notification)
+	})
+
+	return err
+}
+
+func (s *DeadLetterService) ensureConnectionExists(ctx context.Context, connectionId string) error {
+	exists, err := s.connectionRepo.IsHavingConnectionById(ctx, connectionId)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return helpers.NotFoundError("connection with ID %s does not exist", connectionId)
+	}
+	return nil
+}
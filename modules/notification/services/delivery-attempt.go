@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"notification-server/helpers"
+	connectionRepositories "notification-server/modules/connection/repositories"
+	"notification-server/modules/notification/domain"
+	dto "notification-server/modules/notification/dtos"
+	"notification-server/modules/notification/repositories"
+)
+
+type DeliveryAttemptService struct {
+	repo           *repositories.DeliveryAttemptRepository
+	connectionRepo *connectionRepositories.ConnectionRepository
+}
+
+func NewDeliveryAttemptService(repo *repositories.DeliveryAttemptRepository, connectionRepo *connectionRepositories.ConnectionRepository) *DeliveryAttemptService {
+	return &DeliveryAttemptService{
+		repo:           repo,
+		connectionRepo: connectionRepo,
+	}
+}
+
+func (s *DeliveryAttemptService) GetDeliveryAttempts(ctx context.Context, req dto.GetDeliveryAttempts) (domain.NotificationResponse, error) {
+	exists, err := s.connectionRepo.IsHavingConnectionById(ctx, req.ConnectionId)
+	if err != nil {
+		return domain.NotificationResponse{}, err
+	}
+	if !exists {
+		return domain.NotificationResponse{}, helpers.NotFoundError("connection with ID %s does not exist", req.ConnectionId)
+	}
+
+	attempts, nextPageToken, err := s.repo.GetDeliveryAttempts(ctx, req.ConnectionId, req.Outcome, req.From, req.To, req.Limit, req.PageToken)
+	if err != nil {
+		return domain.NotificationResponse{}, err
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    200,
+		Data: domain.GetDeliveryAttempts{
+			List:          attempts,
+			NextPageToken: nextPageToken,
+		},
+	}, nil
+}
@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"notification-server/config"
+	"notification-server/helpers"
+	connectionModels "notification-server/modules/connection/models"
+	connectionRepositories "notification-server/modules/connection/repositories"
+	"notification-server/modules/notification/domain"
+	"notification-server/modules/notification/models"
+	"notification-server/modules/notification/repositories"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	maxStoredResponseBody = 1024
+	rateLimitErrorDelay   = time.Second
+	circuitStateTTL       = 24 * time.Hour
+)
+
+type DeliveryService struct {
+	repo           *repositories.NotificationRepository
+	deadLetterRepo *repositories.DeadLetterRepository
+	attemptRepo    *repositories.DeliveryAttemptRepository
+	connectionRepo *connectionRepositories.ConnectionRepository
+}
+
+func NewDeliveryService(repo *repositories.NotificationRepository, deadLetterRepo *repositories.DeadLetterRepository, attemptRepo *repositories.DeliveryAttemptRepository, connectionRepo *connectionRepositories.ConnectionRepository) *DeliveryService {
+	return &DeliveryService{
+		repo:           repo,
+		deadLetterRepo: deadLetterRepo,
+		attemptRepo:    attemptRepo,
+		connectionRepo: connectionRepo,
+	}
+}
+
+type deliveryOutcome struct {
+	statusCode   int
+	latency      time.Duration
+	responseBody string
+	err          string
+	retryable    bool
+}
+
+func (s *DeliveryService) Deliver(ctx context.Context, workerId string, notification models.Notification) (err error) {
+	attempt := notification.Attempts + 1
+
+	ctx, span := helpers.StartSpan(ctx, "notification.deliver", trace.WithAttributes(
+		attribute.String("notification.id", notification.ID),
+		attribute.String("connection.id", notification.ConnectionId),
+		attribute.Int("notification.attempt", attempt),
+	))
+	defer func() {
+		helpers.RecordSpanError(span, err)
+		span.End()
+	}()
+	ctx = helpers.WithLogFields(ctx, "notification_id", notification.ID, "connection_id", notification.ConnectionId)
+
+	connection, err := s.connectionRepo.GetConnectionByID(ctx, notification.ConnectionId)
+	if err != nil {
+		return err
+	}
+	if connection.ID == "" {
+		return s.deadLetter(ctx, workerId, notification, notification.Attempts, deliveryOutcome{err: "connection no longer exists"})
+	}
+	if connection.Status == connectionModels.StatusSuspended {
+		// Giữ lại thông báo cho đến khi connection được bật lại qua status endpoint
+		return s.repo.DeferNotification(ctx, notification.ID, workerId, time.Now().Add(config.DeliveryConfig.CircuitOpenDuration))
+	}
+	if connection.Status != connectionModels.StatusActive {
+		return s.deadLetter(ctx, workerId, notification, notification.Attempts, deliveryOutcome{err: fmt.Sprintf("connection is %s", connection.Status)})
+	}
+
+	circuitKey := helpers.CircuitBreakerKey(connection.ID)
+	circuit, err := helpers.CircuitAllow(ctx, circuitKey, config.DeliveryConfig.CircuitOpenDuration)
+	if err != nil {
+		helpers.Logger(ctx).Error("Circuit breaker unavailable, delivering anyway", "error", err)
+		circuit = helpers.CircuitState{Allowed: true}
+	}
+	if !circuit.Allowed {
+		s.suspendIfOpenTooLong(ctx, connection, circuit.OpenedAt)
+		return s.repo.DeferNotification(ctx, notification.ID, workerId, time.Now().Add(circuit.RetryAfter))
+	}
+
+	if connection.RateLimitPerSecond > 0 {
+		allowed, wait, err := s.takeRateLimitToken(ctx, connection)
+		if err != nil {
+			helpers.Logger(ctx).Error("Rate limiter unavailable, deferring delivery", "error", err)
+			return s.repo.DeferNotification(ctx, notification.ID, workerId, time.Now().Add(rateLimitErrorDelay))
+		}
+		if !allowed {
+			return s.repo.DeferNotification(ctx, notification.ID, workerId, time.Now().Add(wait))
+		}
+	}
+
+	outcome := s.send(ctx, connection, notification, attempt)
+	if outcome.err == "" {
+		if err := helpers.CircuitRecordSuccess(ctx, circuitKey); err != nil {
+			helpers.Logger(ctx).Error("Failed to reset circuit breaker", "error", err)
+		}
+		if err := s.connectionRepo.MarkApiKeyUsed(ctx, connection.ID, connectionModels.UserDeliveryApiKeyField); err != nil {
+			helpers.Logger(ctx).Error("Failed to record API key usage", "error", err)
+		}
+		s.recordAttempt(ctx, notification, attempt, models.OutcomeSucceeded, outcome)
+		return s.repo.MarkDelivered(ctx, notification.ID, workerId, attempt)
+	}
+
+	// Only failures that point at an unhealthy endpoint trip the breaker; a 4xx means the
+	// receiver is up and simply rejected this particular notification.
+	if outcome.retryable {
+		openedAt, err := helpers.CircuitRecordFailure(ctx, circuitKey, config.DeliveryConfig.CircuitFailureThreshold, config.DeliveryConfig.CircuitOpenDuration, circuitStateTTL)
+		if err != nil {
+			helpers.Logger(ctx).Error("Failed to record circuit breaker failure", "error", err)
+		} else {
+			s.suspendIfOpenTooLong(ctx, connection, openedAt)
+		}
+	}
+
+	maxAttempts := connection.MaxDeliveryAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = config.DeliveryConfig.MaxAttempts
+	}
+
+	if !outcome.retryable || attempt >= maxAttempts {
+		s.recordAttempt(ctx, notification, attempt, models.OutcomeFailed, outcome)
+		return s.deadLetter(ctx, workerId, notification, attempt, outcome)
+	}
+
+	s.recordAttempt(ctx, notification, attempt, models.OutcomeRetrying, outcome)
+	return s.repo.MarkForRetry(ctx, notification.ID, workerId, attempt, time.Now().Add(backoffDelay(attempt)), outcome.err)
+}
+
+// recordAttempt never fails the delivery itself: losing a history entry is better than
+// re-sending a notification the receiver already accepted.
+func (s *DeliveryService) recordAttempt(ctx context.Context, notification models.Notification, attempt int, result string, outcome deliveryOutcome) {
+	helpers.RecordDeliveryAttempt(notification.ConnectionId, result, outcome.latency)
+
+	responseBody := outcome.responseBody
+	if len(responseBody) > maxStoredResponseBody {
+		responseBody = strings.ToValidUTF8(responseBody[:maxStoredResponseBody], "")
+	}
+
+	deliveryAttempt := models.DeliveryAttempt{
+		ID:             primitive.NewObjectID().Hex(),
+		CreatedAt:      time.Now(),
+		NotificationId: notification.ID,
+		ConnectionId:   notification.ConnectionId,
+		Attempt:        attempt,
+		Outcome:        result,
+		StatusCode:     outcome.statusCode,
+		LatencyMs:      outcome.latency.Milliseconds(),
+		ResponseBody:   responseBody,
+		Error:          outcome.err,
+	}
+
+	if err := s.attemptRepo.CreateDeliveryAttempt(ctx, &deliveryAttempt); err != nil {
+		helpers.Logger(ctx).Error("Failed to record delivery attempt", "attempt", attempt, "error", err)
+	}
+}
+
+func (s *DeliveryService) suspendIfOpenTooLong(ctx context.Context, connection connectionModels.Connection, openedAt time.Time) {
+	if openedAt.IsZero() || time.Since(openedAt) < config.DeliveryConfig.CircuitSuspendAfter {
+		return
+	}
+
+	reason := fmt.Sprintf("circuit breaker open since %s after repeated delivery failures", openedAt.UTC().Format(time.RFC3339))
+	suspended, err := s.connectionRepo.SuspendConnection(ctx, connection.ID, reason)
+	if err != nil {
+		helpers.Logger(ctx).Error("Failed to suspend connection", "error", err)
+		return
+	}
+	if suspended {
+		helpers.Logger(ctx).Warn("Connection suspended", "reason", reason)
+	}
+}
+
+func (s *DeliveryService) takeRateLimitToken(ctx context.Context, connection connectionModels.Connection) (bool, time.Duration, error) {
+	burst := connection.RateLimitBurst
+	if burst <= 0 {
+		burst = int(math.Max(1, math.Ceil(connection.RateLimitPerSecond)))
+	}
+
+	return helpers.TakeRateLimitToken(ctx, "rate_limit:connection:"+connection.ID, connection.RateLimitPerSecond, burst)
+}
+
+func (s *DeliveryService) send(ctx context.Context, connection connectionModels.Connection, notification models.Notification, attempt int) deliveryOutcome {
+	body, err := json.Marshal(domain.WebhookNotification{
+		ID:              notification.ID,
+		ConnectionId:    notification.ConnectionId,
+		WebviewServerId: notification.WebviewServerId,
+		BroadcastId:     notification.BroadcastId,
+		Payload:         notification.Payload,
+		CreatedAt:       notification.CreatedAt,
+		Attempt:         attempt,
+	})
+	if err != nil {
+		return deliveryOutcome{err: err.Error()}
+	}
+
+	secrets, err := connection.SigningSecrets(time.Now())
+	if err != nil {
+		return deliveryOutcome{err: err.Error()}
+	}
+	headers := map[string]string{
+		helpers.SignatureHeader: helpers.SignPayload(secrets, body, time.Now()),
+	}
+
+	result, err := helpers.PostWebhook(ctx, connection.UserDeliveryServerWebHookUrl, body, headers)
+	if err != nil {
+		return deliveryOutcome{latency: result.Latency, err: err.Error(), retryable: isRetryableError(err)}
+	}
+	if result.StatusCode < 200 || result.StatusCode >= 300 {
+		return deliveryOutcome{
+			statusCode:   result.StatusCode,
+			latency:      result.Latency,
+			responseBody: result.Body,
+			err:          fmt.Sprintf("user delivery server responded with status %d", result.StatusCode),
+			retryable:    isRetryableStatus(result.StatusCode),
+		}
+	}
+
+	return deliveryOutcome{
+		statusCode:   result.StatusCode,
+		latency:      result.Latency,
+		responseBody: result.Body,
+	}
+}
+
+// deadLetter moves the notification out of the outbox in a single transaction, so a
+// worker that lost its lease cannot leave a dead letter behind for an item that another
+// worker is still delivering.
+func (s *DeliveryService) deadLetter(ctx context.Context, workerId string, notification models.Notification, attempts int, outcome deliveryOutcome) error {
+	session, err := s.repo.StartSession(ctx)
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		if err := s.repo.MarkFailed(sessCtx, notification.ID, workerId, attempts, outcome.err); err != nil {
+			return nil, err
+		}
+
+		deadLetter := models.DeadLetter{
+			ID:                    notification.ID,
+			CreatedAt:             time.Now(),
+			NotificationCreatedAt: notification.CreatedAt,
+			ConnectionId:          notification.ConnectionId,
+			WebviewServerId:       notification.WebviewServerId,
+			BroadcastId:           notification.BroadcastId,
+			Payload:               notification.Payload,
+			Attempts:              attempts,
+			LastStatusCode:        outcome.statusCode,
+			LastError:             outcome.err,
+		}
+		return nil, s.deadLetterRepo.CreateDeadLetter(sessCtx, &deadLetter)
+	})
+
+	return err
+}
+
+func backoffDelay(attempt int) time.Duration {
+	delay := config.DeliveryConfig.BackoffBase
+	for i := 1; i < attempt && delay < config.DeliveryConfig.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > config.DeliveryConfig.BackoffMax {
+		delay = config.DeliveryConfig.BackoffMax
+	}
+
+	// Equal jitter: keep half of the delay and randomise the rest so that receivers
+	// coming back online are not hit by every retry at the same instant.
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+func isRetryableStatus(statusCode int) bool {
+	return statusCode >= 500 || statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests
+}
+
+func isRetryableError(err error) bool {
+	return !errors.Is(err, helpers.ErrInvalidWebhookRequest) && !errors.Is(err, helpers.ErrWebhookUrlNotAllowed)
+}
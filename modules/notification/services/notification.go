@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"notification-server/config"
+	"notification-server/helpers"
+	connectionModels "notification-server/modules/connection/models"
+	connectionRepositories "notification-server/modules/connection/repositories"
+	"notification-server/modules/notification/domain"
+	dto "notification-server/modules/notification/dtos"
+	"notification-server/modules/notification/models"
+	"notification-server/modules/notification/repositories"
+	"time"
+
+	"github.com/go-redis/redis/v7"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	idempotencyInProgress      = "in-progress"
+	idempotencyAcquireAttempts = 3
+	enqueueTimeout             = 10 * time.Second
+	// The in-progress marker only has to outlive one enqueue, so a crashed request does
+	// not lock its Idempotency-Key for the whole IdempotencyTTL.
+	idempotencyLockTTL = enqueueTimeout + 5*time.Second
+)
+
+var (
+	ErrIdempotencyKeyInProgress   = helpers.ConflictError("a request with this Idempotency-Key is still being processed")
+	ErrIdempotencyKeyReused       = errors.New("Idempotency-Key was already used with a different request body")
+	ErrNoBroadcastTargets         = helpers.ValidationError("webview server has no active connections")
+	ErrNotificationNotFound       = helpers.NotFoundError("notification not found")
+	ErrNotificationNotCancellable = helpers.ConflictError("notification has already been sent or cancelled")
+)
+
+type NotificationService struct {
+	repo           *repositories.NotificationRepository
+	connectionRepo *connectionRepositories.ConnectionRepository
+}
+
+func NewNotificationService(repo *repositories.NotificationRepository, connectionRepo *connectionRepositories.ConnectionRepository) *NotificationService {
+	return &NotificationService{
+		repo:           repo,
+		connectionRepo: connectionRepo,
+	}
+}
+
+type idempotentResult struct {
+	Fingerprint string                      `json:"fingerprint"`
+	Response    domain.NotificationResponse `json:"response"`
+}
+
+// SendNotification enqueues the notification. When req.IdempotencyKey is set, the
+// first response for that key is stored in Redis and returned as-is (replayed = true)
+// to any later request carrying the same key on the same connection.
+func (s *NotificationService) SendNotification(ctx context.Context, connection connectionModels.Connection, req dto.SendNotification) (domain.NotificationResponse, bool, error) {
+	if req.IdempotencyKey == "" {
+		response, err := s.enqueue(ctx, connection, req)
+		return response, false, err
+	}
+
+	fingerprint, err := requestFingerprint(req)
+	if err != nil {
+		return domain.NotificationResponse{}, false, err
+	}
+
+	cacheKey := fmt.Sprintf("idempotency:%s:%s", connection.ID, req.IdempotencyKey)
+
+	var acquired bool
+	for attempt := 0; attempt < idempotencyAcquireAttempts; attempt++ {
+		acquired, err = helpers.SetCacheIfNotExists(ctx, cacheKey, idempotencyInProgress, idempotencyLockTTL)
+		if err != nil {
+			return domain.NotificationResponse{}, false, err
+		}
+		if acquired {
+			break
+		}
+
+		cachedData, err := helpers.GetCache(ctx, cacheKey)
+		if err == redis.Nil {
+			// The marker expired between SETNX and GET; try to take it again
+			continue
+		}
+		if err != nil {
+			return domain.NotificationResponse{}, false, err
+		}
+		if cachedData == idempotencyInProgress {
+			return domain.NotificationResponse{}, false, ErrIdempotencyKeyInProgress
+		}
+
+		var cached idempotentResult
+		if err := json.Unmarshal([]byte(cachedData), &cached); err != nil {
+			return domain.NotificationResponse{}, false, err
+		}
+		if cached.Fingerprint != fingerprint {
+			return domain.NotificationResponse{}, false, ErrIdempotencyKeyReused
+		}
+
+		return cached.Response, true, nil
+	}
+	if !acquired {
+		return domain.NotificationResponse{}, false, ErrIdempotencyKeyInProgress
+	}
+
+	enqueueCtx, cancel := context.WithTimeout(ctx, enqueueTimeout)
+	defer cancel()
+
+	response, err := s.enqueue(enqueueCtx, connection, req)
+	if err != nil {
+		// Giải phóng key để client có thể thử lại với cùng Idempotency-Key
+		_ = helpers.DeleteCache(ctx, cacheKey)
+		return response, false, err
+	}
+
+	jsonData, err := json.Marshal(idempotentResult{Fingerprint: fingerprint, Response: response})
+	if err == nil {
+		err = helpers.SetCacheWithTTL(ctx, cacheKey, string(jsonData), config.DeliveryConfig.IdempotencyTTL)
+	}
+	if err != nil {
+		// The notification is already queued, so the request still succeeds; the marker
+		// expires with the lock TTL and a retry after that would enqueue again.
+		helpers.Logger(ctx).Error("Failed to store idempotent response", "idempotency_key", req.IdempotencyKey, "error", err)
+	}
+
+	return response, false, nil
+}
+
+func (s *NotificationService) enqueue(ctx context.Context, connection connectionModels.Connection, req dto.SendNotification) (domain.NotificationResponse, error) {
+	if req.Broadcast {
+		return s.enqueueBroadcast(ctx, connection, req)
+	}
+
+	notification := newPendingNotification(connection, req)
+
+	err := s.repo.CreateNotification(ctx, &notification)
+	if err != nil {
+		return domain.NotificationResponse{
+			Message: "failed to enqueue notification",
+			Code:    500,
+			Data:    nil,
+		}, err
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    202,
+		Data: domain.SendNotification{
+			ID:           notification.ID,
+			ConnectionId: notification.ConnectionId,
+			Status:       notification.Status,
+		},
+	}, nil
+}
+
+// enqueueBroadcast fans the notification out to every active connection of the calling
+// webview server. Each target gets its own outbox item, so workers deliver them
+// independently and a slow receiver only ever holds up its own copy.
+func (s *NotificationService) enqueueBroadcast(ctx context.Context, connection connectionModels.Connection, req dto.SendNotification) (domain.NotificationResponse, error) {
+	connections, err := s.connectionRepo.GetConnectionByWebviewId(ctx, connection.WebviewServerId)
+	if err != nil {
+		return domain.NotificationResponse{
+			Message: "failed to resolve broadcast targets",
+			Code:    500,
+			Data:    nil,
+		}, err
+	}
+
+	broadcastId := primitive.NewObjectID().Hex()
+
+	var notifications []models.Notification
+	for _, target := range connections {
+		if target.Status != connectionModels.StatusActive {
+			continue
+		}
+
+		notification := newPendingNotification(target, req)
+		notification.BroadcastId = broadcastId
+		notifications = append(notifications, notification)
+	}
+
+	if len(notifications) == 0 {
+		return domain.NotificationResponse{
+			Message: "no active connections to broadcast to",
+			Code:    400,
+			Data:    nil,
+		}, ErrNoBroadcastTargets
+	}
+
+	err = s.repo.CreateNotifications(ctx, notifications)
+	if err != nil {
+		return domain.NotificationResponse{
+			Message: "failed to enqueue notification",
+			Code:    500,
+			Data:    nil,
+		}, err
+	}
+
+	result := domain.BroadcastNotification{
+		BroadcastId:   broadcastId,
+		Notifications: make([]domain.SendNotification, 0, len(notifications)),
+	}
+	for _, notification := range notifications {
+		result.Notifications = append(result.Notifications, domain.SendNotification{
+			ID:           notification.ID,
+			ConnectionId: notification.ConnectionId,
+			Status:       notification.Status,
+		})
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    202,
+		Data:    result,
+	}, nil
+}
+
+func newPendingNotification(connection connectionModels.Connection, req dto.SendNotification) models.Notification {
+	now := time.Now()
+
+	notification := models.Notification{
+		ID:              primitive.NewObjectID().Hex(),
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Status:          models.StatusPending,
+		ConnectionId:    connection.ID,
+		WebviewServerId: connection.WebviewServerId,
+		Payload:         req.Payload,
+		Attempts:        0,
+		NextAttemptAt:   now,
+	}
+
+	if req.DeliverAt.After(now) {
+		notification.Status = models.StatusScheduled
+		notification.DeliverAt = req.DeliverAt
+		notification.NextAttemptAt = req.DeliverAt
+	}
+
+	return notification
+}
+
+func (s *NotificationService) CancelNotification(ctx context.Context, connection connectionModels.Connection, req dto.CancelNotification) (domain.NotificationResponse, error) {
+	cancelled, err := s.repo.CancelNotifications(ctx, req.ID, connection.WebviewServerId)
+	if err != nil {
+		return domain.NotificationResponse{
+			Message: "failed to cancel notification",
+			Code:    500,
+			Data:    nil,
+		}, err
+	}
+
+	if cancelled == 0 {
+		count, err := s.repo.CountNotifications(ctx, req.ID, connection.WebviewServerId)
+		if err != nil {
+			return domain.NotificationResponse{
+				Message: "failed to cancel notification",
+				Code:    500,
+				Data:    nil,
+			}, err
+		}
+		if count == 0 {
+			return domain.NotificationResponse{
+				Message: "Notification not found",
+				Code:    404,
+				Data:    nil,
+			}, ErrNotificationNotFound
+		}
+
+		return domain.NotificationResponse{
+			Message: "Notification has already been sent or cancelled",
+			Code:    409,
+			Data:    nil,
+		}, ErrNotificationNotCancellable
+	}
+
+	return domain.NotificationResponse{
+		Message: "success",
+		Code:    200,
+		Data: domain.CancelNotification{
+			ID:        req.ID,
+			Cancelled: cancelled,
+		},
+	}, nil
+}
+
+func requestFingerprint(req dto.SendNotification) (string, error) {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"notification-server/helpers"
+	"notification-server/modules/notification/repositories"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const queueMetricsTimeout = 5 * time.Second
+
+var (
+	queueDepthDesc = prometheus.NewDesc(
+		"notification_queue_depth",
+		"Notifications waiting for delivery or being delivered, by connection.",
+		[]string{"connection"}, nil,
+	)
+	queueOldestAgeDesc = prometheus.NewDesc(
+		"notification_queue_oldest_age_seconds",
+		"Age of the oldest notification waiting for delivery, by connection.",
+		[]string{"connection"}, nil,
+	)
+)
+
+// QueueCollector reads the queue from MongoDB on every scrape instead of tracking it in
+// memory, because the outbox is shared by every replica.
+type QueueCollector struct {
+	repo *repositories.NotificationRepository
+}
+
+func NewQueueCollector(repo *repositories.NotificationRepository) *QueueCollector {
+	return &QueueCollector{repo: repo}
+}
+
+func (c *QueueCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- queueDepthDesc
+	ch <- queueOldestAgeDesc
+}
+
+func (c *QueueCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), queueMetricsTimeout)
+	defer cancel()
+
+	stats, err := c.repo.GetQueueStats(ctx)
+	if err != nil {
+		helpers.Logger(ctx).Error("Failed to collect queue metrics", "error", err)
+		ch <- prometheus.NewInvalidMetric(queueDepthDesc, err)
+		return
+	}
+
+	now := time.Now()
+	for _, stat := range stats {
+		ch <- prometheus.MustNewConstMetric(queueDepthDesc, prometheus.GaugeValue, float64(stat.Depth), stat.ConnectionId)
+		ch <- prometheus.MustNewConstMetric(queueOldestAgeDesc, prometheus.GaugeValue, now.Sub(stat.OldestAt).Seconds(), stat.ConnectionId)
+	}
+}
@@ -0,0 +1,103 @@
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"notification-server/helpers"
+	"notification-server/modules/notification/repositories"
+	"notification-server/modules/notification/services"
+)
+
+type DeliveryWorkerPool struct {
+	repo            *repositories.NotificationRepository
+	deliveryService *services.DeliveryService
+	workers         int
+	pollInterval    time.Duration
+	leaseDuration   time.Duration
+
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	running atomic.Int32
+}
+
+func NewDeliveryWorkerPool(repo *repositories.NotificationRepository, deliveryService *services.DeliveryService, workers int, pollInterval time.Duration, leaseDuration time.Duration) *DeliveryWorkerPool {
+	return &DeliveryWorkerPool{
+		repo:            repo,
+		deliveryService: deliveryService,
+		workers:         workers,
+		pollInterval:    pollInterval,
+		leaseDuration:   leaseDuration,
+	}
+}
+
+func (p *DeliveryWorkerPool) Start(ctx context.Context) {
+	// Workers deliver for every tenant, so they run outside any caller's scope
+	ctx, p.cancel = context.WithCancel(helpers.WithSystemTenant(ctx))
+
+	hostname, _ := os.Hostname()
+	for i := 0; i < p.workers; i++ {
+		workerId := fmt.Sprintf("%s-%d-%d", hostname, os.Getpid(), i)
+		p.wg.Add(1)
+		go p.run(ctx, workerId)
+	}
+
+	slog.Info("Started delivery workers", "workers", p.workers)
+}
+
+// Stop lets in-flight deliveries finish until ctx expires. Anything still running after
+// that keeps its lease and is picked up again by another instance once the lease ends.
+func (p *DeliveryWorkerPool) Stop(ctx context.Context) error {
+	if p.cancel != nil {
+		p.cancel()
+	}
+	return waitGroupWithContext(ctx, &p.wg)
+}
+
+func (p *DeliveryWorkerPool) Running() int {
+	return int(p.running.Load())
+}
+
+func (p *DeliveryWorkerPool) Size() int {
+	return p.workers
+}
+
+func (p *DeliveryWorkerPool) run(ctx context.Context, workerId string) {
+	defer p.wg.Done()
+
+	p.running.Add(1)
+	defer p.running.Add(-1)
+
+	ctx = helpers.WithLogFields(ctx, "worker_id", workerId)
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		notification, err := p.repo.ClaimNextNotification(ctx, workerId, p.leaseDuration)
+		if err != nil && ctx.Err() == nil {
+			helpers.Logger(ctx).Error("Failed to claim notification", "error", err)
+		}
+
+		if err != nil || notification.ID == "" {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.pollInterval):
+			}
+			continue
+		}
+
+		// Delivery keeps going on its own context so a shutdown does not abandon a
+		// request halfway; the lease bounds how long it can take.
+		if err := p.deliveryService.Deliver(context.WithoutCancel(ctx), workerId, notification); err != nil {
+			helpers.Logger(ctx).Error("Failed to deliver notification", "notification_id", notification.ID, "error", err)
+		}
+	}
+}
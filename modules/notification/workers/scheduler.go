@@ -0,0 +1,60 @@
+package workers
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"notification-server/helpers"
+	"notification-server/modules/notification/repositories"
+)
+
+type Scheduler struct {
+	repo     *repositories.NotificationRepository
+	interval time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewScheduler(repo *repositories.NotificationRepository, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		repo:     repo,
+		interval: interval,
+	}
+}
+
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go s.run(ctx)
+
+	slog.Info("Started notification scheduler", "interval", s.interval.String())
+}
+
+func (s *Scheduler) Stop(ctx context.Context) error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return waitGroupWithContext(ctx, &s.wg)
+}
+
+func (s *Scheduler) run(ctx context.Context) {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if _, err := s.repo.ReleaseDueNotifications(ctx, now); err != nil && ctx.Err() == nil {
+				helpers.Logger(ctx).Error("Failed to release due notifications", "error", err)
+			}
+		}
+	}
+}
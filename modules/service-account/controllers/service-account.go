@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"notification-server/middlewares"
+	dto "notification-server/modules/service-account/dtos"
+	"notification-server/modules/service-account/models"
+	"notification-server/modules/service-account/services"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultServiceAccountPageSize = 50
+	maxServiceAccountPageSize     = 200
+)
+
+type ServiceAccountController struct {
+	service *services.ServiceAccountService
+}
+
+func NewServiceAccountController(service *services.ServiceAccountService) *ServiceAccountController {
+	return &ServiceAccountController{service: service}
+}
+
+func (c *ServiceAccountController) GetServiceAccounts(ctx echo.Context) error {
+	var query dto.GetServiceAccounts
+
+	if err := ctx.Bind(&query); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
+
+	if query.Status != "" && !models.IsValidStatus(query.Status) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status type"})
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultServiceAccountPageSize
+	}
+	if query.Limit > maxServiceAccountPageSize {
+		query.Limit = maxServiceAccountPageSize
+	}
+
+	response, err := c.service.GetServiceAccounts(ctx.Request().Context(), query)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *ServiceAccountController) CreateServiceAccount(ctx echo.Context) error {
+	var req dto.CreateServiceAccount
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || len(req.Name) > 100 {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "name is required and must be under 100 characters"})
+	}
+
+	if len(req.Roles) == 0 && !req.SuperAdmin {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "at least one role is required"})
+	}
+	for _, role := range req.Roles {
+		if !middlewares.IsValidRole(role) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "roles must be viewer, operator or admin"})
+		}
+	}
+
+	response, err := c.service.CreateServiceAccount(ctx.Request().Context(), req)
+	if err != nil {
+		if errors.Is(err, services.ErrSuperAdminRequired) {
+			return ctx.JSON(http.StatusForbidden, map[string]string{"error": err.Error()})
+		}
+		return err
+	}
+
+	return ctx.JSON(http.StatusCreated, response)
+}
+
+func (c *ServiceAccountController) ChangeServiceAccountStatus(ctx echo.Context) error {
+	var req dto.ChangeServiceAccountStatus
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+
+	req.ID = strings.TrimSpace(ctx.Param("id"))
+	req.Status = strings.TrimSpace(req.Status)
+
+	if req.ID == "" || req.Status == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "id and status are required"})
+	}
+
+	if !models.IsValidStatus(req.Status) {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid status type"})
+	}
+
+	response, err := c.service.ChangeServiceAccountStatus(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *ServiceAccountController) RevokeTokens(ctx echo.Context) error {
+	id := strings.TrimSpace(ctx.Param("id"))
+	if id == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+
+	response, err := c.service.RevokeTokens(ctx.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *ServiceAccountController) DeleteServiceAccount(ctx echo.Context) error {
+	var req dto.DeleteServiceAccount
+	req.ID = strings.TrimSpace(ctx.Param("id"))
+
+	if req.ID == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "id is required"})
+	}
+
+	response, err := c.service.DeleteServiceAccount(ctx.Request().Context(), req)
+	if err != nil {
+		return err
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
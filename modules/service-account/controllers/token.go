@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"notification-server/helpers"
+	"notification-server/modules/service-account/domain"
+	dto "notification-server/modules/service-account/dtos"
+	"notification-server/modules/service-account/services"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type TokenController struct {
+	service *services.TokenService
+}
+
+func NewTokenController(service *services.TokenService) *TokenController {
+	return &TokenController{service: service}
+}
+
+func (c *TokenController) IssueToken(ctx echo.Context) error {
+	var req dto.IssueToken
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+	req.GrantType = strings.TrimSpace(req.GrantType)
+
+	// Token không được phép nằm lại trong cache của proxy
+	ctx.Response().Header().Set("Cache-Control", "no-store")
+
+	response, err := c.service.IssueToken(ctx.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUnsupportedGrantType):
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		case errors.Is(err, services.ErrInvalidClient), errors.Is(err, services.ErrInvalidGrant):
+			return ctx.JSON(http.StatusUnauthorized, map[string]string{"error": err.Error()})
+		case errors.Is(err, helpers.ErrTokenIssuanceDisabled):
+			return ctx.JSON(http.StatusServiceUnavailable, map[string]string{"error": err.Error()})
+		}
+		helpers.Logger(ctx.Request().Context()).Error("Failed to issue token", "grant_type", req.GrantType, "error", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to issue token"})
+	}
+
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (c *TokenController) RevokeToken(ctx echo.Context) error {
+	var req dto.RevokeToken
+
+	if err := ctx.Bind(&req); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request format"})
+	}
+
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+	}
+
+	if err := c.service.RevokeToken(ctx.Request().Context(), req); err != nil {
+		if errors.Is(err, services.ErrTokenNotRevocable) {
+			return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+		}
+		helpers.Logger(ctx.Request().Context()).Error("Failed to revoke token", "error", err)
+		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": "failed to revoke token"})
+	}
+
+	return ctx.JSON(http.StatusOK, domain.ServiceAccountResponse{
+		Message: "success",
+		Code:    200,
+	})
+}
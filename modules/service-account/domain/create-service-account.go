@@ -0,0 +1,7 @@
+package domain
+
+type CreateServiceAccount struct {
+	ID           string `json:"id"`
+	ClientID     string `json:"clientId"`
+	ClientSecret string `json:"clientSecret"`
+}
@@ -0,0 +1,8 @@
+package domain
+
+import "notification-server/modules/service-account/models"
+
+type GetServiceAccounts struct {
+	List          []models.ServiceAccount `json:"list"`
+	NextPageToken string                  `json:"nextPageToken"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type ChangeServiceAccountStatus struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
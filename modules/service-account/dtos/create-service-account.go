@@ -0,0 +1,7 @@
+package dto
+
+type CreateServiceAccount struct {
+	Name       string   `json:"name"`
+	Roles      []string `json:"roles"`
+	SuperAdmin bool     `json:"superAdmin"`
+}
@@ -0,0 +1,5 @@
+package dto
+
+type DeleteServiceAccount struct {
+	ID string `json:"id"`
+}
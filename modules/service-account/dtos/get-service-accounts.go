@@ -0,0 +1,7 @@
+package dto
+
+type GetServiceAccounts struct {
+	Status    string `query:"status"`
+	Limit     int    `query:"limit"`
+	PageToken string `query:"pageToken"`
+}
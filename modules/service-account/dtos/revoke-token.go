@@ -0,0 +1,5 @@
+package dto
+
+type RevokeToken struct {
+	Token string `json:"token" form:"token"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// RefreshToken is handed out as "<id>.<secret>"; only a salted hash of the secret is
+// stored. Each refresh consumes the token and issues a new one.
+type RefreshToken struct {
+	ID               string    `bson:"_id,omitempty" json:"_id"`
+	CreatedAt        time.Time `bson:"createdAt" json:"createdAt"`
+	ExpiresAt        time.Time `bson:"expiresAt" json:"expiresAt"`
+	ServiceAccountID string    `bson:"serviceAccountId" json:"serviceAccountId"`
+	Salt             string    `bson:"salt" json:"-"`
+	Hash             string    `bson:"hash" json:"-"`
+	RevokedAt        time.Time `bson:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// ServiceAccount lets automation obtain admin tokens with client credentials. Tokens act
+// on behalf of the owner, so the account sees exactly what its owner sees.
+type ServiceAccount struct {
+	ID               string    `bson:"_id,omitempty" json:"_id"`
+	CreatedAt        time.Time `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time `bson:"updatedAt" json:"updatedAt"`
+	Name             string    `bson:"name" json:"name"`
+	Status           string    `bson:"status" json:"status"`
+	OwnerID          string    `bson:"ownerId" json:"ownerId"`
+	ClientID         string    `bson:"clientId" json:"clientId"`
+	ClientSecretSalt string    `bson:"clientSecretSalt" json:"-"`
+	ClientSecretHash string    `bson:"clientSecretHash" json:"-"`
+	Roles            []string  `bson:"roles" json:"roles"`
+	SuperAdmin       bool      `bson:"superAdmin" json:"superAdmin"`
+}
@@ -0,0 +1,101 @@
+package repositories
+
+import (
+	"context"
+	"notification-server/helpers"
+	"notification-server/modules/service-account/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Refresh tokens are only reached through a service account that has already been
+// checked against the tenant, so queries here are not tenant scoped.
+type RefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+func NewRefreshTokenRepository(db *mongo.Database) *RefreshTokenRepository {
+	return &RefreshTokenRepository{
+		collection: db.Collection("refresh-tokens"),
+	}
+}
+
+func (r *RefreshTokenRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "serviceAccountId", Value: 1}}},
+		{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+	})
+	return err
+}
+
+func (r *RefreshTokenRepository) CreateRefreshToken(ctx context.Context, token *models.RefreshToken) error {
+	defer helpers.ObserveMongoOperation("refresh-token", "CreateRefreshToken", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(token.ID)
+	if err != nil {
+		return err
+	}
+
+	tokenDocument := bson.M{
+		"_id":              objectID,
+		"createdAt":        token.CreatedAt,
+		"expiresAt":        token.ExpiresAt,
+		"serviceAccountId": token.ServiceAccountID,
+		"salt":             token.Salt,
+		"hash":             token.Hash,
+	}
+
+	_, err = r.collection.InsertOne(ctx, tokenDocument)
+	return err
+}
+
+func (r *RefreshTokenRepository) GetRefreshTokenByID(ctx context.Context, id string) (models.RefreshToken, error) {
+	defer helpers.ObserveMongoOperation("refresh-token", "GetRefreshTokenByID", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	var token models.RefreshToken
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.RefreshToken{}, nil
+		}
+		return models.RefreshToken{}, err
+	}
+
+	return token, nil
+}
+
+// RevokeRefreshToken reports whether this call revoked the token, so two concurrent
+// refreshes with the same token cannot both succeed.
+func (r *RefreshTokenRepository) RevokeRefreshToken(ctx context.Context, id string) (bool, error) {
+	defer helpers.ObserveMongoOperation("refresh-token", "RevokeRefreshToken", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+func (r *RefreshTokenRepository) RevokeServiceAccountRefreshTokens(ctx context.Context, serviceAccountID string) error {
+	defer helpers.ObserveMongoOperation("refresh-token", "RevokeServiceAccountRefreshTokens", time.Now())
+	_, err := r.collection.UpdateMany(ctx,
+		bson.M{"serviceAccountId": serviceAccountID, "revokedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revokedAt": time.Now()}},
+	)
+	return err
+}
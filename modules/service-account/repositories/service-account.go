@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+	"notification-server/helpers"
+	"notification-server/modules/service-account/models"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ServiceAccountRepository struct {
+	collection *mongo.Collection
+}
+
+func NewServiceAccountRepository(db *mongo.Database) *ServiceAccountRepository {
+	return &ServiceAccountRepository{
+		collection: db.Collection("service-accounts"),
+	}
+}
+
+func (r *ServiceAccountRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "clientId", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "ownerId", Value: 1}, {Key: "_id", Value: 1}}},
+	})
+	return err
+}
+
+func (r *ServiceAccountRepository) CreateServiceAccount(ctx context.Context, account *models.ServiceAccount) error {
+	defer helpers.ObserveMongoOperation("service-account", "CreateServiceAccount", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(account.ID)
+	if err != nil {
+		return err
+	}
+
+	accountDocument := bson.M{
+		"_id":              objectID,
+		"createdAt":        account.CreatedAt,
+		"updatedAt":        account.UpdatedAt,
+		"name":             account.Name,
+		"status":           account.Status,
+		"ownerId":          account.OwnerID,
+		"clientId":         account.ClientID,
+		"clientSecretSalt": account.ClientSecretSalt,
+		"clientSecretHash": account.ClientSecretHash,
+		"roles":            account.Roles,
+		"superAdmin":       account.SuperAdmin,
+	}
+
+	_, err = r.collection.InsertOne(ctx, accountDocument)
+	return err
+}
+
+func (r *ServiceAccountRepository) GetServiceAccounts(ctx context.Context, status string, limit int, nextPageToken string) ([]models.ServiceAccount, string, error) {
+	defer helpers.ObserveMongoOperation("service-account", "GetServiceAccounts", time.Now())
+	var accounts []models.ServiceAccount
+	filter := bson.M{}
+
+	if status != "" {
+		filter["status"] = status
+	}
+	if nextPageToken != "" {
+		tokenID, err := helpers.StringToObjectID(nextPageToken)
+		if err != nil {
+			return nil, "", helpers.ValidationError("invalid nextPageToken: %s", nextPageToken)
+		}
+		filter["_id"] = bson.M{"$gt": tokenID}
+	}
+
+	filter, err := helpers.TenantFilter(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	limitInt64 := int64(limit)
+	cursor, err := r.collection.Find(ctx, filter, &options.FindOptions{
+		Limit: &limitInt64,
+		Sort:  bson.M{"_id": 1},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	defer cursor.Close(ctx)
+
+	var lastID string
+	for cursor.Next(ctx) {
+		var account models.ServiceAccount
+		if err := cursor.Decode(&account); err != nil {
+			return nil, "", err
+		}
+
+		accounts = append(accounts, account)
+		lastID = account.ID
+	}
+
+	return accounts, lastID, nil
+}
+
+func (r *ServiceAccountRepository) GetServiceAccountByID(ctx context.Context, id string) (models.ServiceAccount, error) {
+	defer helpers.ObserveMongoOperation("service-account", "GetServiceAccountByID", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	return r.findOne(ctx, bson.M{"_id": objectID})
+}
+
+func (r *ServiceAccountRepository) GetServiceAccountByClientID(ctx context.Context, clientID string) (models.ServiceAccount, error) {
+	defer helpers.ObserveMongoOperation("service-account", "GetServiceAccountByClientID", time.Now())
+	return r.findOne(ctx, bson.M{"clientId": clientID})
+}
+
+func (r *ServiceAccountRepository) findOne(ctx context.Context, filter bson.M) (models.ServiceAccount, error) {
+	filter, err := helpers.TenantFilter(ctx, filter)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+
+	var account models.ServiceAccount
+	err = r.collection.FindOne(ctx, filter).Decode(&account)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return models.ServiceAccount{}, nil
+		}
+		return models.ServiceAccount{}, err
+	}
+
+	return account, nil
+}
+
+func (r *ServiceAccountRepository) ChangeServiceAccountStatus(ctx context.Context, id string, status string) error {
+	defer helpers.ObserveMongoOperation("service-account", "ChangeServiceAccountStatus", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"status":    status,
+			"updatedAt": time.Now(),
+		},
+	}
+
+	_, err = r.collection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *ServiceAccountRepository) DeleteServiceAccount(ctx context.Context, id string) error {
+	defer helpers.ObserveMongoOperation("service-account", "DeleteServiceAccount", time.Now())
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, filter)
+	return err
+}
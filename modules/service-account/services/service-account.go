@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"notification-server/helpers"
+	auditModels "notification-server/modules/audit-log/models"
+	auditServices "notification-server/modules/audit-log/services"
+	"notification-server/modules/service-account/domain"
+	dto "notification-server/modules/service-account/dtos"
+	"notification-server/modules/service-account/models"
+	"notification-server/modules/service-account/repositories"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrServiceAccountNotFound = helpers.NotFoundError("service account not found")
+	ErrSuperAdminRequired     = errors.New("only a super admin can create a super admin service account")
+)
+
+type ServiceAccountService struct {
+	repo         *repositories.ServiceAccountRepository
+	refreshRepo  *repositories.RefreshTokenRepository
+	auditService *auditServices.AuditLogService
+}
+
+func NewServiceAccountService(repo *repositories.ServiceAccountRepository, refreshRepo *repositories.RefreshTokenRepository, auditService *auditServices.AuditLogService) *ServiceAccountService {
+	return &ServiceAccountService{
+		repo:         repo,
+		refreshRepo:  refreshRepo,
+		auditService: auditService,
+	}
+}
+
+func (s *ServiceAccountService) GetServiceAccounts(ctx context.Context, req dto.GetServiceAccounts) (domain.ServiceAccountResponse, error) {
+	accounts, nextPageToken, err := s.repo.GetServiceAccounts(ctx, req.Status, req.Limit, req.PageToken)
+	if err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+
+	return domain.ServiceAccountResponse{
+		Message: "success",
+		Code:    200,
+		Data: domain.GetServiceAccounts{
+			List:          accounts,
+			NextPageToken: nextPageToken,
+		},
+	}, nil
+}
+
+// CreateServiceAccount returns the client secret in plaintext; it is stored hashed and
+// cannot be shown again.
+func (s *ServiceAccountService) CreateServiceAccount(ctx context.Context, req dto.CreateServiceAccount) (domain.CreateServiceAccount, error) {
+	tenant, _ := helpers.TenantFromContext(ctx)
+	if req.SuperAdmin && !tenant.SuperAdmin {
+		return domain.CreateServiceAccount{}, ErrSuperAdminRequired
+	}
+
+	ownerID, err := helpers.TenantOwnerID(ctx)
+	if err != nil {
+		return domain.CreateServiceAccount{}, err
+	}
+
+	clientID, err := generateSecret(16)
+	if err != nil {
+		return domain.CreateServiceAccount{}, err
+	}
+	clientSecret, err := generateSecret(32)
+	if err != nil {
+		return domain.CreateServiceAccount{}, err
+	}
+	salt, err := helpers.NewApiKeySalt()
+	if err != nil {
+		return domain.CreateServiceAccount{}, err
+	}
+
+	now := time.Now()
+	account := models.ServiceAccount{
+		ID:               primitive.NewObjectID().Hex(),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		Name:             req.Name,
+		Status:           models.StatusActive,
+		OwnerID:          ownerID,
+		ClientID:         clientID,
+		ClientSecretSalt: salt,
+		ClientSecretHash: helpers.HashApiKey(clientSecret, salt),
+		Roles:            req.Roles,
+		SuperAdmin:       req.SuperAdmin,
+	}
+
+	if err := s.repo.CreateServiceAccount(ctx, &account); err != nil {
+		return domain.CreateServiceAccount{}, err
+	}
+
+	s.auditService.Record(ctx, auditModels.ActionCreate, auditModels.TargetServiceAccount, account.ID, nil, account)
+
+	return domain.CreateServiceAccount{
+		ID:           account.ID,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	}, nil
+}
+
+// ChangeServiceAccountStatus revokes outstanding tokens on deactivation; tokens issued
+// earlier would otherwise stay valid until they expire.
+func (s *ServiceAccountService) ChangeServiceAccountStatus(ctx context.Context, req dto.ChangeServiceAccountStatus) (domain.ServiceAccountResponse, error) {
+	account, err := s.repo.GetServiceAccountByID(ctx, req.ID)
+	if err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+	if account.ID == "" {
+		return domain.ServiceAccountResponse{}, ErrServiceAccountNotFound
+	}
+	if account.Status == req.Status {
+		return domain.ServiceAccountResponse{}, helpers.ValidationError("service account with id '%s' already has the requested status '%s'", req.ID, req.Status)
+	}
+
+	if err := s.repo.ChangeServiceAccountStatus(ctx, req.ID, req.Status); err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+	if req.Status == models.StatusInactive {
+		if err := revokeServiceAccountTokens(ctx, s.refreshRepo, req.ID); err != nil {
+			return domain.ServiceAccountResponse{}, err
+		}
+	}
+
+	after := account
+	after.Status = req.Status
+	s.auditService.Record(ctx, auditModels.ActionChangeStatus, auditModels.TargetServiceAccount, req.ID, account, after)
+
+	return domain.ServiceAccountResponse{
+		Message: "success",
+		Code:    200,
+		Data:    req.ID,
+	}, nil
+}
+
+func (s *ServiceAccountService) RevokeTokens(ctx context.Context, id string) (domain.ServiceAccountResponse, error) {
+	account, err := s.repo.GetServiceAccountByID(ctx, id)
+	if err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+	if account.ID == "" {
+		return domain.ServiceAccountResponse{}, ErrServiceAccountNotFound
+	}
+
+	if err := revokeServiceAccountTokens(ctx, s.refreshRepo, id); err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+
+	s.auditService.Record(ctx, auditModels.ActionRevokeTokens, auditModels.TargetServiceAccount, id, account, account)
+
+	return domain.ServiceAccountResponse{
+		Message: "success",
+		Code:    200,
+		Data:    id,
+	}, nil
+}
+
+func (s *ServiceAccountService) DeleteServiceAccount(ctx context.Context, req dto.DeleteServiceAccount) (domain.ServiceAccountResponse, error) {
+	account, err := s.repo.GetServiceAccountByID(ctx, req.ID)
+	if err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+	if account.ID == "" {
+		return domain.ServiceAccountResponse{}, ErrServiceAccountNotFound
+	}
+
+	if err := s.repo.DeleteServiceAccount(ctx, req.ID); err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+	if err := revokeServiceAccountTokens(ctx, s.refreshRepo, req.ID); err != nil {
+		return domain.ServiceAccountResponse{}, err
+	}
+
+	s.auditService.Record(ctx, auditModels.ActionDelete, auditModels.TargetServiceAccount, req.ID, account, nil)
+
+	return domain.ServiceAccountResponse{
+		Message: "success",
+		Code:    200,
+		Data:    req.ID,
+	}, nil
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"notification-server/config"
+	"notification-server/helpers"
+	"notification-server/modules/service-account/domain"
+	dto "notification-server/modules/service-account/dtos"
+	"notification-server/modules/service-account/models"
+	"notification-server/modules/service-account/repositories"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	ErrUnsupportedGrantType = errors.New("grant_type must be client_credentials or refresh_token")
+	ErrInvalidClient        = errors.New("invalid client credentials")
+	ErrInvalidGrant         = errors.New("invalid or expired refresh token")
+	ErrTokenNotRevocable    = errors.New("token has no jti; revoke the service account's tokens instead")
+)
+
+type TokenService struct {
+	accountRepo *repositories.ServiceAccountRepository
+	refreshRepo *repositories.RefreshTokenRepository
+}
+
+func NewTokenService(accountRepo *repositories.ServiceAccountRepository, refreshRepo *repositories.RefreshTokenRepository) *TokenService {
+	return &TokenService{
+		accountRepo: accountRepo,
+		refreshRepo: refreshRepo,
+	}
+}
+
+func (s *TokenService) IssueToken(ctx context.Context, req dto.IssueToken) (domain.Token, error) {
+	if config.AuthConfig.SigningAlgorithm == "" {
+		return domain.Token{}, helpers.ErrTokenIssuanceDisabled
+	}
+
+	// Endpoint này không có người gọi đã xác thực, tài khoản được tìm trên toàn hệ thống
+	ctx = helpers.WithSystemTenant(ctx)
+
+	var account models.ServiceAccount
+	var err error
+	switch req.GrantType {
+	case dto.GrantClientCredentials:
+		account, err = s.authenticateClient(ctx, req.ClientID, req.ClientSecret)
+	case dto.GrantRefreshToken:
+		account, err = s.consumeRefreshToken(ctx, req.RefreshToken)
+	default:
+		return domain.Token{}, ErrUnsupportedGrantType
+	}
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	return s.issue(ctx, account)
+}
+
+func (s *TokenService) authenticateClient(ctx context.Context, clientID string, clientSecret string) (models.ServiceAccount, error) {
+	if clientID == "" || clientSecret == "" {
+		return models.ServiceAccount{}, ErrInvalidClient
+	}
+
+	account, err := s.accountRepo.GetServiceAccountByClientID(ctx, clientID)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+	if account.ID == "" || account.Status != models.StatusActive || !helpers.CompareApiKeyHash(clientSecret, account.ClientSecretSalt, account.ClientSecretHash) {
+		return models.ServiceAccount{}, ErrInvalidClient
+	}
+
+	return account, nil
+}
+
+// consumeRefreshToken rotates the refresh token. Presenting one that was already used
+// means it was copied, so every token of the account is revoked.
+func (s *TokenService) consumeRefreshToken(ctx context.Context, refreshToken string) (models.ServiceAccount, error) {
+	stored, err := s.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+	if stored.ID == "" || !time.Now().Before(stored.ExpiresAt) {
+		return models.ServiceAccount{}, ErrInvalidGrant
+	}
+
+	consumed := false
+	if stored.RevokedAt.IsZero() {
+		consumed, err = s.refreshRepo.RevokeRefreshToken(ctx, stored.ID)
+		if err != nil {
+			return models.ServiceAccount{}, err
+		}
+	}
+	if !consumed {
+		if err := revokeServiceAccountTokens(ctx, s.refreshRepo, stored.ServiceAccountID); err != nil {
+			return models.ServiceAccount{}, err
+		}
+		return models.ServiceAccount{}, ErrInvalidGrant
+	}
+
+	account, err := s.accountRepo.GetServiceAccountByID(ctx, stored.ServiceAccountID)
+	if err != nil {
+		return models.ServiceAccount{}, err
+	}
+	if account.ID == "" || account.Status != models.StatusActive {
+		return models.ServiceAccount{}, ErrInvalidGrant
+	}
+
+	return account, nil
+}
+
+func (s *TokenService) findRefreshToken(ctx context.Context, refreshToken string) (models.RefreshToken, error) {
+	id, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok || !primitive.IsValidObjectID(id) {
+		return models.RefreshToken{}, nil
+	}
+
+	stored, err := s.refreshRepo.GetRefreshTokenByID(ctx, id)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+	if stored.ID == "" || !helpers.CompareApiKeyHash(secret, stored.Salt, stored.Hash) {
+		return models.RefreshToken{}, nil
+	}
+
+	return stored, nil
+}
+
+func (s *TokenService) issue(ctx context.Context, account models.ServiceAccount) (domain.Token, error) {
+	now := time.Now()
+	jti, err := generateSecret(16)
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	accessToken, err := helpers.SignJWT(helpers.JWTClaims{
+		UserID:     account.OwnerID,
+		SuperAdmin: account.SuperAdmin,
+		Roles:      account.Roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
+			Subject:   account.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(config.AuthConfig.AccessTokenTTL)),
+		},
+	})
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	secret, err := generateSecret(32)
+	if err != nil {
+		return domain.Token{}, err
+	}
+	salt, err := helpers.NewApiKeySalt()
+	if err != nil {
+		return domain.Token{}, err
+	}
+
+	refreshToken := models.RefreshToken{
+		ID:               primitive.NewObjectID().Hex(),
+		CreatedAt:        now,
+		ExpiresAt:        now.Add(config.AuthConfig.RefreshTokenTTL),
+		ServiceAccountID: account.ID,
+		Salt:             salt,
+		Hash:             helpers.HashApiKey(secret, salt),
+	}
+	if err := s.refreshRepo.CreateRefreshToken(ctx, &refreshToken); err != nil {
+		return domain.Token{}, err
+	}
+
+	return domain.Token{
+		AccessToken:  accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(config.AuthConfig.AccessTokenTTL.Seconds()),
+		RefreshToken: refreshToken.ID + "." + secret,
+	}, nil
+}
+
+// RevokeToken accepts either an access token or a refresh token. Unknown tokens are
+// ignored, so the endpoint cannot be used to probe which tokens exist.
+func (s *TokenService) RevokeToken(ctx context.Context, req dto.RevokeToken) error {
+	if strings.Count(req.Token, ".") == 2 {
+		claims, err := helpers.ParseJWT(ctx, req.Token, jwt.WithoutClaimsValidation())
+		if err != nil || claims.ExpiresAt == nil {
+			return nil
+		}
+		if claims.ID == "" {
+			return ErrTokenNotRevocable
+		}
+		return helpers.RevokeToken(ctx, claims.ID, claims.ExpiresAt.Time)
+	}
+
+	stored, err := s.findRefreshToken(ctx, req.Token)
+	if err != nil || stored.ID == "" {
+		return err
+	}
+	_, err = s.refreshRepo.RevokeRefreshToken(ctx, stored.ID)
+	return err
+}
+
+func revokeServiceAccountTokens(ctx context.Context, refreshRepo *repositories.RefreshTokenRepository, serviceAccountID string) error {
+	if err := refreshRepo.RevokeServiceAccountRefreshTokens(ctx, serviceAccountID); err != nil {
+		return err
+	}
+	return helpers.RevokeSubject(ctx, serviceAccountID)
+}
+
+func generateSecret(size int) (string, error) {
+	bytes := make([]byte, size)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
+}
@@ -27,7 +27,7 @@ func (c *UserDeliveryController) GetUserDeliveryList(ctx echo.Context) error {
 
 	response, err := c.service.GetUserDeliveryList(ctx.Request().Context(), query.Keyword, string(query.Status), query.Limit, query.PageToken)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -46,7 +46,7 @@ func (c *UserDeliveryController) CreateUserDelivery(ctx echo.Context) error {
 
 	response, err := c.service.CreateUserDelivery(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, response)
@@ -73,7 +73,7 @@ func (c *UserDeliveryController) UpdateUserDelivery(ctx echo.Context) error {
 
 	response, err := c.service.UpdateUserDeliveryService(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -100,7 +100,7 @@ func (c *UserDeliveryController) ChangeUserDeliveryStatus(ctx echo.Context) erro
 
 	response, err := c.service.ChangeUserDeliveryStatus(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -117,7 +117,7 @@ func (c *UserDeliveryController) DeleteUserDelivery(ctx echo.Context) error {
 
 	response, err := c.service.DeleteUserDeliveryService(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
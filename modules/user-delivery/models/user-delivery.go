@@ -10,4 +10,5 @@ type UserDelivery struct {
 	UpdatedAt time.Time `bson:"updatedAt" json:"updatedAt"`
 	Name      string    `bson:"name" json:"name"`
 	Status    string    `bson:"status" json:"status"`
+	OwnerID   string    `bson:"ownerId" json:"ownerId"`
 }
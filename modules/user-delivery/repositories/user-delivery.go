@@ -29,6 +29,7 @@ func (r *UserDeliveryRepository) StartSession(ctx context.Context) (mongo.Sessio
 }
 
 func (r *UserDeliveryRepository) GetUserDeliveryList(ctx context.Context, keyword string, status string, limit int, nextPageToken string) ([]models.UserDelivery, string, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "GetUserDeliveryList", time.Now())
 	var userDeliveries []models.UserDelivery
 	filter := bson.M{}
 
@@ -45,6 +46,11 @@ func (r *UserDeliveryRepository) GetUserDeliveryList(ctx context.Context, keywor
 		}
 	}
 
+	filter, err := helpers.TenantFilter(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -67,6 +73,7 @@ func (r *UserDeliveryRepository) GetUserDeliveryList(ctx context.Context, keywor
 			UpdatedAt time.Time          `bson:"updatedAt"`
 			Name      string             `bson:"name"`
 			Status    string             `bson:"status"`
+			OwnerID   string             `bson:"ownerId"`
 		}
 
 		if err := cursor.Decode(&temp); err != nil {
@@ -78,6 +85,7 @@ func (r *UserDeliveryRepository) GetUserDeliveryList(ctx context.Context, keywor
 		UserDelivery.UpdatedAt = temp.UpdatedAt
 		UserDelivery.Name = temp.Name
 		UserDelivery.Status = temp.Status
+		UserDelivery.OwnerID = temp.OwnerID
 
 		userDeliveries = append(userDeliveries, UserDelivery)
 		lastID = UserDelivery.ID
@@ -87,6 +95,7 @@ func (r *UserDeliveryRepository) GetUserDeliveryList(ctx context.Context, keywor
 }
 
 func (r *UserDeliveryRepository) CreateUserDelivery(ctx context.Context, userDelivery *models.UserDelivery) error {
+	defer helpers.ObserveMongoOperation("user-delivery", "CreateUserDelivery", time.Now())
 
 	objectID, err := primitive.ObjectIDFromHex(userDelivery.ID)
 	if err != nil {
@@ -99,6 +108,7 @@ func (r *UserDeliveryRepository) CreateUserDelivery(ctx context.Context, userDel
 		"updatedAt": userDelivery.UpdatedAt,
 		"name":      userDelivery.Name,
 		"status":    userDelivery.Status,
+		"ownerId":   userDelivery.OwnerID,
 	}
 
 	_, err = r.list.InsertOne(ctx, userDeliveryDocument)
@@ -110,7 +120,11 @@ func (r *UserDeliveryRepository) CreateUserDelivery(ctx context.Context, userDel
 }
 
 func (r *UserDeliveryRepository) IsUserDeliveryExistsByName(ctx context.Context, name string) (bool, error) {
-	filter := bson.M{"name": name}
+	defer helpers.ObserveMongoOperation("user-delivery", "IsUserDeliveryExistsByName", time.Now())
+	filter, err := helpers.TenantFilter(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
 
 	count, err := r.list.CountDocuments(ctx, filter)
 	if err != nil {
@@ -121,13 +135,19 @@ func (r *UserDeliveryRepository) IsUserDeliveryExistsByName(ctx context.Context,
 }
 
 func (r *UserDeliveryRepository) GetUserDeliveryByID(ctx context.Context, id string) (*models.UserDelivery, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "GetUserDeliveryByID", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
 	var userDelivery models.UserDelivery
-	err = r.list.FindOne(ctx, bson.M{"_id": objectID}).Decode(&userDelivery)
+	err = r.list.FindOne(ctx, filter).Decode(&userDelivery)
 	if err != nil {
 		return nil, err
 	}
@@ -136,18 +156,24 @@ func (r *UserDeliveryRepository) GetUserDeliveryByID(ctx context.Context, id str
 }
 
 func (r *UserDeliveryRepository) UpdateUserDelivery(ctx context.Context, id string, name string) (string, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "UpdateUserDelivery", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"name": name,
 		},
 	}
 
-	_, err = r.list.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	_, err = r.list.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return "", err
 	}
@@ -156,12 +182,18 @@ func (r *UserDeliveryRepository) UpdateUserDelivery(ctx context.Context, id stri
 }
 
 func (r *UserDeliveryRepository) IsUserDeliveryExistsByID(ctx context.Context, id string) (bool, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "IsUserDeliveryExistsByID", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return false, err
 	}
 
-	count, err := r.list.CountDocuments(ctx, bson.M{"_id": objectID})
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return false, err
+	}
+
+	count, err := r.list.CountDocuments(ctx, filter)
 	if err != nil {
 		return false, err
 	}
@@ -170,18 +202,24 @@ func (r *UserDeliveryRepository) IsUserDeliveryExistsByID(ctx context.Context, i
 }
 
 func (r *UserDeliveryRepository) ChangeUserDeliveryStatus(ctx context.Context, id string, status string) (string, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "ChangeUserDeliveryStatus", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"status": status,
 		},
 	}
 
-	_, err = r.list.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	_, err = r.list.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return "", err
 	}
@@ -190,12 +228,18 @@ func (r *UserDeliveryRepository) ChangeUserDeliveryStatus(ctx context.Context, i
 }
 
 func (r *UserDeliveryRepository) DeleteUserDelivery(ctx context.Context, id string) (string, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "DeleteUserDelivery", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
-	_, err = r.list.DeleteOne(ctx, bson.M{"_id": objectID})
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.list.DeleteOne(ctx, filter)
 	if err != nil {
 		return "", err
 	}
@@ -204,16 +248,33 @@ func (r *UserDeliveryRepository) DeleteUserDelivery(ctx context.Context, id stri
 }
 
 func (r *UserDeliveryRepository) IsUserDeliveryActive(ctx context.Context, id string) (bool, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "IsUserDeliveryActive", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return false, err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return false, err
+	}
+
 	var userDelivery models.UserDelivery
-	err = r.list.FindOne(ctx, bson.M{"_id": objectID}).Decode(&userDelivery)
+	err = r.list.FindOne(ctx, filter).Decode(&userDelivery)
 	if err != nil {
 		return false, err
 	}
 
 	return userDelivery.Status == string(models.StatusActive), nil // Assuming StatusActive is defined in models
 }
+
+// BackfillOwnerID assigns user delivery servers created before tenants existed to ownerID. It is
+// safe to run on every start.
+func (r *UserDeliveryRepository) BackfillOwnerID(ctx context.Context, ownerID string) (int64, error) {
+	defer helpers.ObserveMongoOperation("user-delivery", "BackfillOwnerID", time.Now())
+	result, err := r.list.UpdateMany(ctx, bson.M{"ownerId": bson.M{"$in": bson.A{nil, ""}}}, bson.M{"$set": bson.M{"ownerId": ownerID}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
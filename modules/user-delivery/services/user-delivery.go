@@ -3,8 +3,12 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"notification-server/helpers"
+	auditModels "notification-server/modules/audit-log/models"
+	auditServices "notification-server/modules/audit-log/services"
+	connectionModels "notification-server/modules/connection/models"
 	connectionRepositories "notification-server/modules/connection/repositories"
 	"notification-server/modules/user-delivery/domain"
 	dto "notification-server/modules/user-delivery/dtos"
@@ -20,21 +24,23 @@ import (
 type UserDeliveryService struct {
 	repo           *repositories.UserDeliveryRepository
 	connectionRepo *connectionRepositories.ConnectionRepository
+	auditService   *auditServices.AuditLogService
 	webviewRepo    *webviewRepositories.WebViewRepository
 }
 
-func NewUserDeliveryService(repo *repositories.UserDeliveryRepository, connectionRepo *connectionRepositories.ConnectionRepository, webviewRepo *webviewRepositories.WebViewRepository) *UserDeliveryService {
+func NewUserDeliveryService(repo *repositories.UserDeliveryRepository, connectionRepo *connectionRepositories.ConnectionRepository, webviewRepo *webviewRepositories.WebViewRepository, auditService *auditServices.AuditLogService) *UserDeliveryService {
 	return &UserDeliveryService{
 		repo:           repo,
 		connectionRepo: connectionRepo,
 		webviewRepo:    webviewRepo,
+		auditService:   auditService,
 	}
 }
 
 func (s *UserDeliveryService) GetUserDeliveryList(ctx context.Context, keyword string, status string, limit int, nextPageToken string) (domain.UserDeliveryResponse, error) {
-	cacheKey := fmt.Sprintf("user_delivery_list:%s:%s:%d:%s", keyword, status, limit, nextPageToken)
+	cacheKey := fmt.Sprintf("user_delivery_list:%s:%s:%s:%d:%s", helpers.TenantCacheKey(ctx), keyword, status, limit, nextPageToken)
 
-	cachedData, err := helpers.GetCache(cacheKey)
+	cachedData, err := helpers.GetCache(ctx, cacheKey)
 	if err == nil {
 		var cachedResponse domain.UserDeliveryResponse
 		if jsonErr := json.Unmarshal([]byte(cachedData), &cachedResponse); jsonErr == nil {
@@ -62,7 +68,7 @@ func (s *UserDeliveryService) GetUserDeliveryList(ctx context.Context, keyword s
 	}
 
 	jsonData, _ := json.Marshal(response)
-	_ = helpers.SetCache(cacheKey, string(jsonData))
+	_ = helpers.SetCache(ctx, cacheKey, string(jsonData))
 
 	return response, nil
 }
@@ -70,18 +76,15 @@ func (s *UserDeliveryService) GetUserDeliveryList(ctx context.Context, keyword s
 func (s *UserDeliveryService) CreateUserDelivery(ctx context.Context, req dto.CreateUserDelivery) (domain.UserDeliveryResponse, error) {
 	exists, err := s.repo.IsUserDeliveryExistsByName(ctx, req.Name)
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "failed to check existing User Delivery",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 	if exists {
-		return domain.UserDeliveryResponse{
-			Message: "User Delivery name already exists",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("user delivery with name '%s' already exists", req.Name)
+		return domain.UserDeliveryResponse{}, helpers.ConflictError("user delivery with name '%s' already exists", req.Name)
+	}
+
+	ownerID, err := helpers.TenantOwnerID(ctx)
+	if err != nil {
+		return domain.UserDeliveryResponse{}, err
 	}
 
 	objectID := primitive.NewObjectID()
@@ -93,17 +96,16 @@ func (s *UserDeliveryService) CreateUserDelivery(ctx context.Context, req dto.Cr
 		UpdatedAt: now,
 		Name:      req.Name,
 		Status:    string(models.StatusInactive),
+		OwnerID:   ownerID,
 	}
 
 	err = s.repo.CreateUserDelivery(ctx, &userDelivery)
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "failed to create User Delivery",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 
+	s.auditService.Record(ctx, auditModels.ActionCreate, auditModels.TargetUserDelivery, userDelivery.ID, nil, userDelivery)
+
 	responseData := domain.CreateUserDelivery{ID: userDelivery.ID}
 	return domain.UserDeliveryResponse{
 		Message: "success",
@@ -114,33 +116,26 @@ func (s *UserDeliveryService) CreateUserDelivery(ctx context.Context, req dto.Cr
 
 func (s *UserDeliveryService) ChangeUserDeliveryStatus(ctx context.Context, req dto.ChangeUserDeliveryStatus) (domain.UserDeliveryResponse, error) {
 	userDelivery, err := s.repo.GetUserDeliveryByID(ctx, req.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.UserDeliveryResponse{}, helpers.NotFoundError("user delivery with id '%s' does not exist", req.ID)
+	}
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "User Delivery not found, no status change applied",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 
 	if userDelivery.Status == req.Status {
-		return domain.UserDeliveryResponse{
-			Message: "User Delivery status is already the same as the requested status",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("user delivery with id '%s' already has the requested status '%s'", req.ID, req.Status)
+		return domain.UserDeliveryResponse{}, helpers.ValidationError("user delivery with id '%s' already has the requested status '%s'", req.ID, req.Status)
 	}
 
 	session, err := s.repo.StartSession(ctx)
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "Failed to start transaction",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 	defer session.EndSession(ctx)
 
+	var deactivated []connectionModels.Connection
 	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		deactivated = nil
 		objectID, updateErr := s.repo.ChangeUserDeliveryStatus(sessCtx, req.ID, req.Status)
 		if updateErr != nil {
 			return nil, updateErr
@@ -157,6 +152,7 @@ func (s *UserDeliveryService) ChangeUserDeliveryStatus(ctx context.Context, req
 				if err != nil {
 					return nil, err
 				}
+				deactivated = append(deactivated, conn)
 			}
 		}
 
@@ -164,11 +160,16 @@ func (s *UserDeliveryService) ChangeUserDeliveryStatus(ctx context.Context, req
 	})
 
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "Failed to update User Delivery and associated connections",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
+	}
+
+	after := *userDelivery
+	after.Status = req.Status
+	s.auditService.Record(ctx, auditModels.ActionChangeStatus, auditModels.TargetUserDelivery, req.ID, userDelivery, after)
+	for _, conn := range deactivated {
+		connAfter := conn
+		connAfter.Status = connectionModels.StatusInactive
+		s.auditService.Record(ctx, auditModels.ActionChangeStatus, auditModels.TargetConnection, conn.ID, conn, connAfter)
 	}
 
 	return domain.UserDeliveryResponse{
@@ -181,43 +182,32 @@ func (s *UserDeliveryService) ChangeUserDeliveryStatus(ctx context.Context, req
 func (s *UserDeliveryService) UpdateUserDeliveryService(ctx context.Context, req dto.UpdateUserDelivery) (domain.UserDeliveryResponse, error) {
 	exists, err := s.repo.IsUserDeliveryExistsByID(ctx, req.ID)
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "failed to check existing User Delivery",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 	if !exists {
-		return domain.UserDeliveryResponse{
-			Message: "User Delivery not found",
-			Code:    404,
-			Data:    nil,
-		}, fmt.Errorf("user delivery with id '%s' does not exist", req.ID)
+		return domain.UserDeliveryResponse{}, helpers.NotFoundError("user delivery with id '%s' does not exist", req.ID)
+	}
+
+	before, err := s.repo.GetUserDeliveryByID(ctx, req.ID)
+	if err != nil {
+		return domain.UserDeliveryResponse{}, err
 	}
 
 	if existsByName, err := s.repo.IsUserDeliveryExistsByName(ctx, req.Name); err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "failed to check existing User Delivery by name",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	} else if existsByName {
-		return domain.UserDeliveryResponse{
-			Message: "User Delivery name already exists",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("user delivery with name '%s' already exists", req.Name)
+		return domain.UserDeliveryResponse{}, helpers.ConflictError("user delivery with name '%s' already exists", req.Name)
 	}
 
 	updateID, updateErr := s.repo.UpdateUserDelivery(ctx, req.ID, req.Name)
 	if updateErr != nil {
-		return domain.UserDeliveryResponse{
-			Message: "failed to update User Delivery",
-			Code:    500,
-			Data:    nil,
-		}, updateErr
+		return domain.UserDeliveryResponse{}, updateErr
 	}
 
+	after := *before
+	after.Name = req.Name
+	s.auditService.Record(ctx, auditModels.ActionUpdate, auditModels.TargetUserDelivery, req.ID, before, after)
+
 	return domain.UserDeliveryResponse{
 		Message: "success",
 		Code:    200,
@@ -228,31 +218,26 @@ func (s *UserDeliveryService) UpdateUserDeliveryService(ctx context.Context, req
 func (s *UserDeliveryService) DeleteUserDeliveryService(ctx context.Context, req dto.DeleteUserDelivery) (domain.UserDeliveryResponse, error) {
 	exists, err := s.repo.IsUserDeliveryExistsByID(ctx, req.ID)
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "failed to check existing User Delivery",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 	if !exists {
-		return domain.UserDeliveryResponse{
-			Message: "User Delivery not found",
-			Code:    404,
-			Data:    nil,
-		}, fmt.Errorf("user delivery with id '%s' does not exist", req.ID)
+		return domain.UserDeliveryResponse{}, helpers.NotFoundError("user delivery with id '%s' does not exist", req.ID)
+	}
+
+	before, err := s.repo.GetUserDeliveryByID(ctx, req.ID)
+	if err != nil {
+		return domain.UserDeliveryResponse{}, err
 	}
 
 	session, err := s.repo.StartSession(ctx)
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "Failed to start transaction",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
 	}
 	defer session.EndSession(ctx)
 
+	var deleted []connectionModels.Connection
 	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		deleted = nil
 		connections, connErr := s.connectionRepo.GetConnectionByUserDeliveryId(sessCtx, req.ID)
 		if connErr != nil {
 			return nil, connErr
@@ -262,6 +247,7 @@ func (s *UserDeliveryService) DeleteUserDeliveryService(ctx context.Context, req
 			if err := s.connectionRepo.DeleteConnection(sessCtx, conn.ID); err != nil {
 				return nil, err
 			}
+			deleted = append(deleted, conn)
 		}
 
 		deletedID, deleteErr := s.repo.DeleteUserDelivery(sessCtx, req.ID)
@@ -273,12 +259,13 @@ func (s *UserDeliveryService) DeleteUserDeliveryService(ctx context.Context, req
 	})
 
 	if err != nil {
-		return domain.UserDeliveryResponse{
-			Message: "Failed to delete User Delivery and associated connections",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.UserDeliveryResponse{}, err
+	}
+
+	for _, conn := range deleted {
+		s.auditService.Record(ctx, auditModels.ActionDelete, auditModels.TargetConnection, conn.ID, conn, nil)
 	}
+	s.auditService.Record(ctx, auditModels.ActionDelete, auditModels.TargetUserDelivery, req.ID, before, nil)
 
 	return domain.UserDeliveryResponse{
 		Message: "success",
@@ -27,7 +27,7 @@ func (c *WebViewController) GetWebViewList(ctx echo.Context) error {
 
 	response, err := c.service.GetWebviewListService(ctx.Request().Context(), query.Keyword, string(query.Status), query.Limit, query.PageToken)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -46,7 +46,7 @@ func (c *WebViewController) CreateWebView(ctx echo.Context) error {
 
 	response, err := c.service.CreateWebviewService(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusCreated, response)
@@ -73,7 +73,7 @@ func (c *WebViewController) UpdateWebView(ctx echo.Context) error {
 
 	response, err := c.service.UpdateWebviewService(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -100,7 +100,7 @@ func (c *WebViewController) ChangeWebViewStatus(ctx echo.Context) error {
 
 	response, err := c.service.ChangeWebviewStatus(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -117,7 +117,7 @@ func (c *WebViewController) DeleteWebview(ctx echo.Context) error {
 
 	response, err := c.service.DeleteWebviewService(ctx.Request().Context(), req)
 	if err != nil {
-		return ctx.JSON(http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return err
 	}
 
 	return ctx.JSON(http.StatusOK, response)
@@ -29,6 +29,7 @@ func (r *WebViewRepository) StartSession(ctx context.Context) (mongo.Session, er
 }
 
 func (r *WebViewRepository) GetWebviewList(ctx context.Context, keyword string, status string, limit int, nextPageToken string) ([]models.WebViewServer, string, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "GetWebviewList", time.Now())
 	var webviews []models.WebViewServer
 	filter := bson.M{}
 
@@ -45,6 +46,11 @@ func (r *WebViewRepository) GetWebviewList(ctx context.Context, keyword string,
 		}
 	}
 
+	filter, err := helpers.TenantFilter(ctx, filter)
+	if err != nil {
+		return nil, "", err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
@@ -67,6 +73,7 @@ func (r *WebViewRepository) GetWebviewList(ctx context.Context, keyword string,
 			UpdatedAt time.Time          `bson:"updatedAt"`
 			Name      string             `bson:"name"`
 			Status    string             `bson:"status"`
+			OwnerID   string             `bson:"ownerId"`
 		}
 
 		if err := cursor.Decode(&temp); err != nil {
@@ -78,6 +85,7 @@ func (r *WebViewRepository) GetWebviewList(ctx context.Context, keyword string,
 		webview.UpdatedAt = temp.UpdatedAt
 		webview.Name = temp.Name
 		webview.Status = temp.Status
+		webview.OwnerID = temp.OwnerID
 
 		webviews = append(webviews, webview)
 		lastID = webview.ID
@@ -87,6 +95,7 @@ func (r *WebViewRepository) GetWebviewList(ctx context.Context, keyword string,
 }
 
 func (r *WebViewRepository) CreateWebview(ctx context.Context, webview *models.WebViewServer) error {
+	defer helpers.ObserveMongoOperation("webview-server", "CreateWebview", time.Now())
 
 	objectID, err := primitive.ObjectIDFromHex(webview.ID)
 	if err != nil {
@@ -99,6 +108,7 @@ func (r *WebViewRepository) CreateWebview(ctx context.Context, webview *models.W
 		"updatedAt": webview.UpdatedAt,
 		"name":      webview.Name,
 		"status":    webview.Status,
+		"ownerId":   webview.OwnerID,
 	}
 
 	_, err = r.list.InsertOne(ctx, webviewDocument)
@@ -110,7 +120,11 @@ func (r *WebViewRepository) CreateWebview(ctx context.Context, webview *models.W
 }
 
 func (r *WebViewRepository) IsWebviewExistsByName(ctx context.Context, name string) (bool, error) {
-	filter := bson.M{"name": name}
+	defer helpers.ObserveMongoOperation("webview-server", "IsWebviewExistsByName", time.Now())
+	filter, err := helpers.TenantFilter(ctx, bson.M{"name": name})
+	if err != nil {
+		return false, err
+	}
 
 	count, err := r.list.CountDocuments(ctx, filter)
 	if err != nil {
@@ -121,13 +135,19 @@ func (r *WebViewRepository) IsWebviewExistsByName(ctx context.Context, name stri
 }
 
 func (r *WebViewRepository) GetWebviewByID(ctx context.Context, id string) (*models.WebViewServer, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "GetWebviewByID", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return nil, err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return nil, err
+	}
+
 	var webview models.WebViewServer
-	err = r.list.FindOne(ctx, bson.M{"_id": objectID}).Decode(&webview)
+	err = r.list.FindOne(ctx, filter).Decode(&webview)
 	if err != nil {
 		return nil, err
 	}
@@ -136,18 +156,24 @@ func (r *WebViewRepository) GetWebviewByID(ctx context.Context, id string) (*mod
 }
 
 func (r *WebViewRepository) UpdateWebview(ctx context.Context, id string, name string) (string, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "UpdateWebview", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"name": name,
 		},
 	}
 
-	_, err = r.list.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	_, err = r.list.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return "", err
 	}
@@ -156,12 +182,18 @@ func (r *WebViewRepository) UpdateWebview(ctx context.Context, id string, name s
 }
 
 func (r *WebViewRepository) IsWebviewExistsByID(ctx context.Context, id string) (bool, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "IsWebviewExistsByID", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return false, err
 	}
 
-	count, err := r.list.CountDocuments(ctx, bson.M{"_id": objectID})
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return false, err
+	}
+
+	count, err := r.list.CountDocuments(ctx, filter)
 	if err != nil {
 		return false, err
 	}
@@ -170,18 +202,24 @@ func (r *WebViewRepository) IsWebviewExistsByID(ctx context.Context, id string)
 }
 
 func (r *WebViewRepository) ChangeWebviewStatus(ctx context.Context, id string, status string) (string, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "ChangeWebviewStatus", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
 	update := bson.M{
 		"$set": bson.M{
 			"status": status,
 		},
 	}
 
-	_, err = r.list.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	_, err = r.list.UpdateOne(ctx, filter, update)
 	if err != nil {
 		return "", err
 	}
@@ -190,12 +228,18 @@ func (r *WebViewRepository) ChangeWebviewStatus(ctx context.Context, id string,
 }
 
 func (r *WebViewRepository) DeleteWebview(ctx context.Context, id string) (string, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "DeleteWebview", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return "", err
 	}
 
-	_, err = r.list.DeleteOne(ctx, bson.M{"_id": objectID})
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return "", err
+	}
+
+	_, err = r.list.DeleteOne(ctx, filter)
 	if err != nil {
 		return "", err
 	}
@@ -204,16 +248,33 @@ func (r *WebViewRepository) DeleteWebview(ctx context.Context, id string) (strin
 }
 
 func (r *WebViewRepository) IsWebviewActive(ctx context.Context, id string) (bool, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "IsWebviewActive", time.Now())
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
 		return false, err
 	}
 
+	filter, err := helpers.TenantFilter(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return false, err
+	}
+
 	var webview models.WebViewServer
-	err = r.list.FindOne(ctx, bson.M{"_id": objectID}).Decode(&webview)
+	err = r.list.FindOne(ctx, filter).Decode(&webview)
 	if err != nil {
 		return false, err
 	}
 
 	return webview.Status == string(models.StatusActive), nil
 }
+
+// BackfillOwnerID assigns webview servers created before tenants existed to ownerID. It is
+// safe to run on every start.
+func (r *WebViewRepository) BackfillOwnerID(ctx context.Context, ownerID string) (int64, error) {
+	defer helpers.ObserveMongoOperation("webview-server", "BackfillOwnerID", time.Now())
+	result, err := r.list.UpdateMany(ctx, bson.M{"ownerId": bson.M{"$in": bson.A{nil, ""}}}, bson.M{"$set": bson.M{"ownerId": ownerID}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
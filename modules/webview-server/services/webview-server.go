@@ -3,13 +3,17 @@ package services
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"notification-server/helpers"
+	auditModels "notification-server/modules/audit-log/models"
+	auditServices "notification-server/modules/audit-log/services"
+	connectionModels "notification-server/modules/connection/models"
 	connectionRepositories "notification-server/modules/connection/repositories"
 	"notification-server/modules/webview-server/domain"
 	dto "notification-server/modules/webview-server/dtos"
 	"notification-server/modules/webview-server/models"
 	"notification-server/modules/webview-server/repositories"
-	"notification-server/helpers"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -19,16 +23,17 @@ import (
 type WebViewService struct {
 	repo           *repositories.WebViewRepository
 	connectionRepo *connectionRepositories.ConnectionRepository
+	auditService   *auditServices.AuditLogService
 }
 
-func NewWebviewService(repo *repositories.WebViewRepository, connectionRepo *connectionRepositories.ConnectionRepository) *WebViewService {
-	return &WebViewService{repo: repo, connectionRepo: connectionRepo}
+func NewWebviewService(repo *repositories.WebViewRepository, connectionRepo *connectionRepositories.ConnectionRepository, auditService *auditServices.AuditLogService) *WebViewService {
+	return &WebViewService{repo: repo, connectionRepo: connectionRepo, auditService: auditService}
 }
 
 func (s *WebViewService) GetWebviewListService(ctx context.Context, keyword string, status string, limit int, nextPageToken string) (domain.WebViewResponse, error) {
-	cacheKey := fmt.Sprintf("webview_list:%s:%s:%d:%s", keyword, status, limit, nextPageToken)
+	cacheKey := fmt.Sprintf("webview_list:%s:%s:%s:%d:%s", helpers.TenantCacheKey(ctx), keyword, status, limit, nextPageToken)
 
-	cachedData, err := helpers.GetCache(cacheKey)
+	cachedData, err := helpers.GetCache(ctx, cacheKey)
 	if err == nil {
 		var cachedResponse domain.WebViewResponse
 		if jsonErr := json.Unmarshal([]byte(cachedData), &cachedResponse); jsonErr == nil {
@@ -56,7 +61,7 @@ func (s *WebViewService) GetWebviewListService(ctx context.Context, keyword stri
 	}
 
 	jsonData, _ := json.Marshal(response)
-	_ = helpers.SetCache(cacheKey, string(jsonData))
+	_ = helpers.SetCache(ctx, cacheKey, string(jsonData))
 
 	return response, nil
 }
@@ -64,18 +69,15 @@ func (s *WebViewService) GetWebviewListService(ctx context.Context, keyword stri
 func (s *WebViewService) CreateWebviewService(ctx context.Context, req dto.CreateWebviewServer) (domain.WebViewResponse, error) {
 	exists, err := s.repo.IsWebviewExistsByName(ctx, req.Name)
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "failed to check existing WebView",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 	if exists {
-		return domain.WebViewResponse{
-			Message: "WebView name already exists",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("webview with name '%s' already exists", req.Name)
+		return domain.WebViewResponse{}, helpers.ConflictError("webview with name '%s' already exists", req.Name)
+	}
+
+	ownerID, err := helpers.TenantOwnerID(ctx)
+	if err != nil {
+		return domain.WebViewResponse{}, err
 	}
 
 	objectID := primitive.NewObjectID()
@@ -87,17 +89,16 @@ func (s *WebViewService) CreateWebviewService(ctx context.Context, req dto.Creat
 		UpdatedAt: now,
 		Name:      req.Name,
 		Status:    string(models.StatusInactive),
+		OwnerID:   ownerID,
 	}
 
 	err = s.repo.CreateWebview(ctx, &webview)
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "failed to create WebView",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 
+	s.auditService.Record(ctx, auditModels.ActionCreate, auditModels.TargetWebviewServer, webview.ID, nil, webview)
+
 	responseData := domain.CreateWebViewServer{ID: webview.ID}
 
 	return domain.WebViewResponse{
@@ -110,43 +111,32 @@ func (s *WebViewService) CreateWebviewService(ctx context.Context, req dto.Creat
 func (s *WebViewService) UpdateWebviewService(ctx context.Context, req dto.UpdateWebviewServer) (domain.WebViewResponse, error) {
 	exists, err := s.repo.IsWebviewExistsByID(ctx, req.ID)
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "failed to check existing WebView",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 	if !exists {
-		return domain.WebViewResponse{
-			Message: "WebView not found",
-			Code:    404,
-			Data:    nil,
-		}, fmt.Errorf("webview with id '%s' does not exist", req.ID)
+		return domain.WebViewResponse{}, helpers.NotFoundError("webview with id '%s' does not exist", req.ID)
+	}
+
+	before, err := s.repo.GetWebviewByID(ctx, req.ID)
+	if err != nil {
+		return domain.WebViewResponse{}, err
 	}
 
 	if existsByName, err := s.repo.IsWebviewExistsByName(ctx, req.Name); err != nil {
-		return domain.WebViewResponse{
-			Message: "failed to check existing WebView by name",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	} else if existsByName {
-		return domain.WebViewResponse{
-			Message: "WebView name already exists",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("webview with name '%s' already exists", req.Name)
+		return domain.WebViewResponse{}, helpers.ConflictError("webview with name '%s' already exists", req.Name)
 	}
 
 	updateID, updateErr := s.repo.UpdateWebview(ctx, req.ID, req.Name)
 	if updateErr != nil {
-		return domain.WebViewResponse{
-			Message: "failed to update WebView",
-			Code:    500,
-			Data:    nil,
-		}, updateErr
+		return domain.WebViewResponse{}, updateErr
 	}
 
+	after := *before
+	after.Name = req.Name
+	s.auditService.Record(ctx, auditModels.ActionUpdate, auditModels.TargetWebviewServer, req.ID, before, after)
+
 	return domain.WebViewResponse{
 		Message: "success",
 		Code:    200,
@@ -156,33 +146,26 @@ func (s *WebViewService) UpdateWebviewService(ctx context.Context, req dto.Updat
 
 func (s *WebViewService) ChangeWebviewStatus(ctx context.Context, req dto.ChangeWebviewServerStatus) (domain.WebViewResponse, error) {
 	webview, err := s.repo.GetWebviewByID(ctx, req.ID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.WebViewResponse{}, helpers.NotFoundError("webview with id '%s' does not exist", req.ID)
+	}
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "WebView not found, no status change applied",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 
 	if webview.Status == req.Status {
-		return domain.WebViewResponse{
-			Message: "WebView status is already the same as the requested status",
-			Code:    400,
-			Data:    nil,
-		}, fmt.Errorf("webview with id '%s' already has the requested status '%s'", req.ID, req.Status)
+		return domain.WebViewResponse{}, helpers.ValidationError("webview with id '%s' already has the requested status '%s'", req.ID, req.Status)
 	}
 
 	session, err := s.repo.StartSession(ctx)
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "Failed to start transaction",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 	defer session.EndSession(ctx)
 
+	var deactivated []connectionModels.Connection
 	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		deactivated = nil
 		objectID, updateErr := s.repo.ChangeWebviewStatus(sessCtx, req.ID, req.Status)
 		if updateErr != nil {
 			return nil, updateErr
@@ -199,6 +182,7 @@ func (s *WebViewService) ChangeWebviewStatus(ctx context.Context, req dto.Change
 				if err != nil {
 					return nil, err
 				}
+				deactivated = append(deactivated, conn)
 			}
 		}
 
@@ -206,11 +190,16 @@ func (s *WebViewService) ChangeWebviewStatus(ctx context.Context, req dto.Change
 	})
 
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "Failed to update WebView and associated connections",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
+	}
+
+	after := *webview
+	after.Status = req.Status
+	s.auditService.Record(ctx, auditModels.ActionChangeStatus, auditModels.TargetWebviewServer, req.ID, webview, after)
+	for _, conn := range deactivated {
+		connAfter := conn
+		connAfter.Status = connectionModels.StatusInactive
+		s.auditService.Record(ctx, auditModels.ActionChangeStatus, auditModels.TargetConnection, conn.ID, conn, connAfter)
 	}
 
 	return domain.WebViewResponse{
@@ -224,31 +213,26 @@ func (s *WebViewService) DeleteWebviewService(ctx context.Context, req dto.Delet
 
 	exists, err := s.repo.IsWebviewExistsByID(ctx, req.ID)
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "failed to check existing WebView",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 	if !exists {
-		return domain.WebViewResponse{
-			Message: "WebView not found",
-			Code:    404,
-			Data:    nil,
-		}, fmt.Errorf("webview with id '%s' does not exist", req.ID)
+		return domain.WebViewResponse{}, helpers.NotFoundError("webview with id '%s' does not exist", req.ID)
+	}
+
+	before, err := s.repo.GetWebviewByID(ctx, req.ID)
+	if err != nil {
+		return domain.WebViewResponse{}, err
 	}
 
 	session, err := s.repo.StartSession(ctx)
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "Failed to start transaction",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
 	}
 	defer session.EndSession(ctx)
 
+	var deleted []connectionModels.Connection
 	result, err := session.WithTransaction(ctx, func(sessCtx mongo.SessionContext) (interface{}, error) {
+		deleted = nil
 		connections, connErr := s.connectionRepo.GetConnectionByWebviewId(sessCtx, req.ID)
 		if connErr != nil {
 			return nil, connErr
@@ -258,6 +242,7 @@ func (s *WebViewService) DeleteWebviewService(ctx context.Context, req dto.Delet
 			if err := s.connectionRepo.DeleteConnection(sessCtx, conn.ID); err != nil {
 				return nil, err
 			}
+			deleted = append(deleted, conn)
 		}
 
 		deletedID, deleteErr := s.repo.DeleteWebview(sessCtx, req.ID)
@@ -269,12 +254,13 @@ func (s *WebViewService) DeleteWebviewService(ctx context.Context, req dto.Delet
 	})
 
 	if err != nil {
-		return domain.WebViewResponse{
-			Message: "Failed to delete WebView and associated connections",
-			Code:    500,
-			Data:    nil,
-		}, err
+		return domain.WebViewResponse{}, err
+	}
+
+	for _, conn := range deleted {
+		s.auditService.Record(ctx, auditModels.ActionDelete, auditModels.TargetConnection, conn.ID, conn, nil)
 	}
+	s.auditService.Record(ctx, auditModels.ActionDelete, auditModels.TargetWebviewServer, req.ID, before, nil)
 
 	return domain.WebViewResponse{
 		Message: "success",